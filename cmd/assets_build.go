@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/iagapie/go-spring/modules/sys/asset"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/datasource"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/logger"
+	"github.com/urfave/cli/v2"
+	"sort"
+)
+
+var AssetsBuild = &cli.Command{
+	Name:   "assets:build",
+	Usage:  "Build the bundles of the combine calls found in the theme templates",
+	Action: runAssetsBuild,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "theme",
+			Aliases: []string{"t"},
+			Usage:   "Build only this theme",
+		},
+	},
+}
+
+func runAssetsBuild(ctx *cli.Context) error {
+	var cfg config.Cfg
+	if err := helper.ReadConfig(&cfg, ctx.StringSlice("config")...); err != nil {
+		return err
+	}
+
+	log := logger.New(logger.WithDebug(cfg.App.Debug))
+
+	theme.SetThemesPath(fmt.Sprintf("%s/frontend", cfg.CMS.ThemesPath))
+	theme.SetDatasource(datasource.NewFile(log))
+	combiner := asset.NewCombiner(cfg.Assets, log)
+
+	themes := theme.Themes()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		if only := ctx.String("theme"); only == "" || only == name {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w: %s", theme.ErrNotFound, ctx.String("theme"))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		calls, err := theme.CombineCalls(themes[name])
+		if err != nil {
+			return fmt.Errorf("theme %s: %w", name, err)
+		}
+
+		for _, files := range calls {
+			uri, err := theme.Combine(combiner, themes[name], files...)
+			if err != nil {
+				return fmt.Errorf("theme %s: %w", name, err)
+			}
+			fmt.Println("Built", uri, files)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/scaffold"
+	"github.com/urfave/cli/v2"
+	"os"
+)
+
+const makefile = "Makefile"
+
+// scaffoldOptions reads the plugins path from the config and the module path from ./go.mod.
+func scaffoldOptions(ctx *cli.Context) (scaffold.Options, error) {
+	var cfg config.Cfg
+	if err := helper.ReadConfig(&cfg, ctx.StringSlice("config")...); err != nil {
+		return scaffold.Options{}, err
+	}
+
+	module, err := scaffold.ModulePath(".")
+	if err != nil {
+		return scaffold.Options{}, err
+	}
+
+	opts := scaffold.Options{
+		Module:      module,
+		PluginsPath: cfg.CMS.PluginsPath,
+	}
+	if _, err = os.Stat(makefile); err == nil {
+		opts.Makefile = makefile
+	}
+	return opts, nil
+}
+
+func printCreated(files []string) {
+	for _, file := range files {
+		fmt.Println("Created", file)
+	}
+}
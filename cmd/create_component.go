@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+var CreateComponent = &cli.Command{
+	Name:      "create:component",
+	Usage:     "Create a new component in an existing plugin",
+	ArgsUsage: "author.name ComponentName",
+	Action:    runCreateComponent,
+}
+
+func runCreateComponent(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("usage: create:component author.name ComponentName")
+	}
+
+	author, name, err := scaffold.ParseCode(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	opts, err := scaffoldOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	files, err := scaffold.CreateComponent(opts, author, name, ctx.Args().Get(1))
+	printCreated(files)
+	return err
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+var CreatePlugin = &cli.Command{
+	Name:      "create:plugin",
+	Usage:     "Create the skeleton of a new plugin",
+	ArgsUsage: "author.name",
+	Action:    runCreatePlugin,
+}
+
+func runCreatePlugin(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: create:plugin author.name")
+	}
+
+	author, name, err := scaffold.ParseCode(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	opts, err := scaffoldOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	files, err := scaffold.CreatePlugin(opts, author, name)
+	printCreated(files)
+	return err
+}
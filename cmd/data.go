@@ -38,17 +38,11 @@ func initData(ctx *cli.Context) (*__data, error) {
 		return nil, err
 	}
 
-	log.Infoln("auto migrate")
-	if err = postgres.AutoMigrate(&user.User{}); err != nil {
-		postgres.Close()
-		return nil, err
-	}
-
 	log.Infoln("user storage initializing")
 	userStorage := userdb.NewStorage(postgres, log.Entry)
 
 	log.Infoln("user service initializing")
-	userService := user.NewService(userStorage, encoder)
+	userService := user.NewService(userStorage, encoder, cfg.App.Name)
 
 	return &__data{
 		cfg:         cfg,
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/backend/account"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/iagapie/go-spring/modules/sys/mail"
+	"github.com/iagapie/go-spring/modules/sys/queue"
+	"github.com/iagapie/go-spring/modules/sys/token"
+)
+
+// initMail creates the mail manager. When the mail queue is enabled messages are dispatched as jobs of q
+// and sent by queue:work.
+func initMail(data *__data, q *queue.Queue) (*mail.Manager, error) {
+	data.log.Infoln("mailer initializing")
+	mailer, err := mail.New(data.cfg.Mail, data.log)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.cfg.Mail.Queue {
+		data.log.Infoln("mail queue initializing")
+		mailer = mail.NewQueue(data.cfg.Mail, q, mailer)
+	}
+
+	templates := mail.NewTemplates(func() []string {
+		t := theme.ActiveTheme()
+		if t == nil {
+			return nil
+		}
+		chain, _ := t.Chain()
+		dirs := make([]string, 0, len(chain))
+		for _, c := range chain {
+			dirs = append(dirs, c.MailPath())
+		}
+		return dirs
+	}, account.Templates())
+
+	return mail.NewManager(mailer, templates), nil
+}
+
+func initAccount(data *__data, rdb redis.UniversalClient, mailManager *mail.Manager, sessions account.Sessions) account.Service {
+	data.log.Infoln("account service initializing")
+	tokenManager := token.New(token.WithJWTKeys(data.cfg.JWT.SigningKeys), token.WithAudience(token.AudienceAccount))
+	return account.NewService(data.cfg, data.userService, tokenManager, mailManager, sessions, rdb, data.log.Entry)
+}
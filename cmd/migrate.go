@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/migrate"
+)
+
+// checkMigrations refuses to go on while the database schema is behind the code.
+func checkMigrations(data *__data, migrator *migrate.Migrator) error {
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		for _, migration := range pending {
+			data.log.Warnf("pending migration: %s %s", migration.Source, migration.ID())
+		}
+		return fmt.Errorf("there are %d pending migration(s), run migrate:up first", len(pending))
+	}
+
+	return nil
+}
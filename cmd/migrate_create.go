@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/backend/migrations"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/migrate"
+	"github.com/urfave/cli/v2"
+	"path/filepath"
+	"strings"
+)
+
+var MigrateCreate = &cli.Command{
+	Name:   "migrate:create",
+	Usage:  "Create a new pair of up/down migration files",
+	Action: runMigrateCreate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Aliases:  []string{"n"},
+			Usage:    "Migration name, e.g. create_posts_table",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    "plugin",
+			Aliases: []string{"p"},
+			Usage:   "Create the migration in the migrations directory of this plugin (author.name)",
+		},
+	},
+}
+
+func runMigrateCreate(ctx *cli.Context) error {
+	dir := migrations.Dir
+
+	if code := ctx.String("plugin"); code != "" {
+		var cfg config.Cfg
+		if err := helper.ReadConfig(&cfg, ctx.StringSlice("config")...); err != nil {
+			return err
+		}
+
+		parts := strings.Split(code, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("plugin must be in the author.name format, got %s", code)
+		}
+		dir = filepath.Join(cfg.CMS.PluginsPath, parts[0], parts[1], "migrations")
+	}
+
+	up, down, err := migrate.Create(dir, ctx.String("name"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Created", up)
+	fmt.Println("Created", down)
+	return nil
+}
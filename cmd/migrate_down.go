@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"github.com/urfave/cli/v2"
+)
+
+var MigrateDown = &cli.Command{
+	Name:   "migrate:down",
+	Usage:  "Roll back the last batch of database migrations",
+	Action: runMigrateDown,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "steps",
+			Usage: "Roll back this number of migrations instead of the last batch",
+		},
+	},
+}
+
+func runMigrateDown(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	_, m, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	done, err := m.Down(context.Background(), ctx.Int("steps"))
+	if err != nil {
+		return err
+	}
+
+	if len(done) == 0 {
+		data.log.Infoln("nothing to roll back")
+	} else {
+		data.log.Infof("%d migration(s) rolled back", len(done))
+	}
+	return nil
+}
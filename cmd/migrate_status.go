@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+var MigrateStatus = &cli.Command{
+	Name:   "migrate:status",
+	Usage:  "Show which database migrations are applied",
+	Action: runMigrateStatus,
+}
+
+func runMigrateStatus(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	_, m, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	items, err := m.Status(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tMIGRATION\tBATCH\tAPPLIED AT")
+	for _, item := range items {
+		batch, appliedAt := "-", "pending"
+		if item.AppliedAt != nil {
+			batch = fmt.Sprint(item.Batch)
+			appliedAt = item.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Source, item.ID(), batch, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"github.com/urfave/cli/v2"
+)
+
+var MigrateUp = &cli.Command{
+	Name:   "migrate:up",
+	Usage:  "Apply all pending database migrations",
+	Action: runMigrateUp,
+}
+
+func runMigrateUp(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	plugManager, m, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	done, err := m.Up(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// new plugins are installed once the core schema is up to date, that runs their migrations too
+	if err = plugManager.InstallNew(context.Background()); err != nil {
+		return err
+	}
+
+	if len(done) == 0 {
+		data.log.Infoln("nothing to migrate")
+	} else {
+		data.log.Infof("%d migration(s) applied", len(done))
+	}
+	return nil
+}
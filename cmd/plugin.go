@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"github.com/iagapie/go-spring/modules/backend/migrations"
+	"github.com/iagapie/go-spring/modules/sys/migrate"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	plugindb "github.com/iagapie/go-spring/modules/sys/plugin/db"
+	"github.com/iagapie/go-spring/modules/sys/plugin/remote"
+)
+
+// initPlugins loads the plugins with their persisted states. With installNew the plugins seen
+// for the first time are installed and enabled.
+func initPlugins(data *__data, installNew bool) (*plugin.Manager, *migrate.Migrator, error) {
+	data.log.Infoln("plugin manager initializing")
+	plugManager, err := plugin.New(data.cfg.CMS.PluginsPath, data.log, remote.NewLoader(data.log))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data.log.Infoln("migrator initializing")
+	migrator := migrate.New(data.db, data.log)
+	if err = migrator.Add(migrate.SourceCore, migrations.FS()); err != nil {
+		return nil, nil, err
+	}
+
+	data.log.Infoln("plugin manager: Boot")
+	if err = plugManager.Boot(context.Background(), plugindb.NewStorage(data.db, data.log.Entry), data.db, migrator); err != nil {
+		return nil, nil, err
+	}
+
+	// new plugins are installed once the core schema is up to date, until then migrate:up installs them
+	if installNew {
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, migration := range pending {
+			if migration.Source == migrate.SourceCore {
+				return plugManager, migrator, nil
+			}
+		}
+
+		if err = plugManager.InstallNew(context.Background()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return plugManager, migrator, nil
+}
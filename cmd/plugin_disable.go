@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"github.com/urfave/cli/v2"
+)
+
+var PluginDisable = &cli.Command{
+	Name:   "plugin:disable",
+	Usage:  "Disable a plugin without removing its data",
+	Action: runPluginDisable,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "code",
+			Aliases:  []string{"c"},
+			Usage:    "Plugin code",
+			Required: true,
+		},
+	},
+}
+
+func runPluginDisable(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	plugManager, _, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	code := ctx.String("code")
+	if err = plugManager.Disable(context.Background(), code); err != nil {
+		return err
+	}
+
+	data.log.Infof("plugin %s was disabled", code)
+	return nil
+}
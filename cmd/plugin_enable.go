@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"github.com/urfave/cli/v2"
+)
+
+var PluginEnable = &cli.Command{
+	Name:   "plugin:enable",
+	Usage:  "Enable a plugin, installing it first when needed",
+	Action: runPluginEnable,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "code",
+			Aliases:  []string{"c"},
+			Usage:    "Plugin code",
+			Required: true,
+		},
+	},
+}
+
+func runPluginEnable(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	plugManager, _, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	code := ctx.String("code")
+	if err = plugManager.Enable(context.Background(), code); err != nil {
+		return err
+	}
+
+	data.log.Infof("plugin %s was enabled", code)
+	return nil
+}
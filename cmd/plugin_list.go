@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+var PluginList = &cli.Command{
+	Name:   "plugin:list",
+	Usage:  "List plugins and their state",
+	Action: runPluginList,
+}
+
+func runPluginList(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	plugManager, _, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, 0, len(plugManager.States()))
+	for code := range plugManager.States() {
+		codes = append(codes, code)
+	}
+	for _, i := range plugManager.All() {
+		if _, ok := plugManager.State(i.Plugin().Details().Code); !ok {
+			codes = append(codes, i.Plugin().Details().Code)
+		}
+	}
+	sort.Strings(codes)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CODE\tNAME\tVERSION\tSTATE\tFILE")
+	for _, code := range codes {
+		name, version, file := "-", "-", "missing"
+		if i, ok := plugManager.GetByCode(code); ok {
+			details := i.Plugin().Details()
+			name, version, file = details.Name, details.Version, i.File()
+			if i.IsStatic() {
+				file = "static"
+			}
+		}
+
+		state := "new"
+		if s, ok := plugManager.State(code); ok {
+			switch {
+			case !s.Installed:
+				state = "uninstalled"
+			case s.Enabled:
+				state = "enabled"
+			default:
+				state = "disabled"
+			}
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", code, name, version, state, file)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"github.com/urfave/cli/v2"
+)
+
+var PluginUninstall = &cli.Command{
+	Name:   "plugin:uninstall",
+	Usage:  "Uninstall a plugin and roll back its migrations",
+	Action: runPluginUninstall,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "code",
+			Aliases:  []string{"c"},
+			Usage:    "Plugin code",
+			Required: true,
+		},
+	},
+}
+
+func runPluginUninstall(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	plugManager, _, err := initPlugins(data, false)
+	if err != nil {
+		return err
+	}
+
+	code := ctx.String("code")
+	if err = plugManager.Uninstall(context.Background(), code); err != nil {
+		return err
+	}
+
+	data.log.Infof("plugin %s was uninstalled", code)
+	return nil
+}
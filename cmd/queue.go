@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/iagapie/go-spring/modules/sys/queue"
+)
+
+func initQueue(data *__data, rdb redis.UniversalClient, plugManager *plugin.Manager) *queue.Queue {
+	data.log.Infoln("job queue initializing")
+	q := queue.New(data.cfg.Queue, rdb, data.log)
+
+	for _, info := range plugManager.Enabled() {
+		if regJobs, ok := info.Plugin().(queue.PluginRegisterJobs); ok {
+			details := info.Plugin().Details()
+			for jobType, h := range regJobs.RegisterJobs() {
+				data.log.Debugf("job queue: %s[%s] registers job %s", details.Name, details.Code, jobType)
+				q.Register(jobType, h)
+			}
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/urfave/cli/v2"
+)
+
+var QueueWork = &cli.Command{
+	Name:   "queue:work",
+	Usage:  "Start processing background jobs",
+	Action: runQueueWork,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "queue",
+			Aliases: []string{"q"},
+			Usage:   "Queue name, defaults to queue.name from the configuration",
+		},
+		&cli.IntFlag{
+			Name:    "concurrency",
+			Aliases: []string{"n"},
+			Usage:   "Number of jobs processed at the same time, defaults to queue.concurrency from the configuration",
+		},
+	},
+}
+
+func runQueueWork(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	rdb := initRedis(data)
+	defer func() {
+		if err = rdb.Close(); err != nil {
+			data.log.Error(err)
+		}
+	}()
+
+	plugManager, migrator, err := initPlugins(data, true)
+	if err != nil {
+		return err
+	}
+
+	if err = checkMigrations(data, migrator); err != nil {
+		return err
+	}
+
+	initTheme(data)
+
+	jobQueue := initQueue(data, rdb, plugManager)
+
+	mailManager, err := initMail(data, jobQueue)
+	if err != nil {
+		return err
+	}
+
+	s := spring.New(data.cfg, data.log)
+	s.Mail = mailManager
+	s.Queue = jobQueue
+
+	data.log.Infoln("plugin manager: RegisterAll")
+	plugManager.RegisterAll(s)
+
+	concurrency := data.cfg.Queue.Concurrency
+	if ctx.IsSet("concurrency") {
+		concurrency = ctx.Int("concurrency")
+	}
+
+	return s.Queue.Worker(ctx.String("queue"), concurrency).Run()
+}
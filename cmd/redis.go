@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/go-redis/redis/v8"
+)
+
+func initRedis(data *__data) *redis.Client {
+	data.log.Infoln("redis initializing")
+	return redis.NewClient(&redis.Options{
+		Addr:     data.cfg.Redis.Addr,
+		Password: data.cfg.Redis.Password,
+		DB:       0,
+	})
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/iagapie/go-spring/modules/sys/schedule"
+)
+
+func initSchedule(data *__data, rdb redis.UniversalClient, plugManager *plugin.Manager) (*schedule.Scheduler, error) {
+	data.log.Infoln("scheduler initializing")
+	s := schedule.New(data.cfg.Schedule, rdb, data.log)
+
+	for _, info := range plugManager.Enabled() {
+		if regSchedule, ok := info.Plugin().(schedule.PluginRegisterSchedule); ok {
+			details := info.Plugin().Details()
+			for name, task := range regSchedule.RegisterSchedule() {
+				data.log.Debugf("scheduler: %s[%s] registers task %s (%s)", details.Name, details.Code, name, task.Spec)
+				if err := s.Add(name, task); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/urfave/cli/v2"
+)
+
+var ScheduleRun = &cli.Command{
+	Name:   "schedule:run",
+	Usage:  "Start running the scheduled tasks",
+	Action: runScheduleRun,
+}
+
+func runScheduleRun(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	rdb := initRedis(data)
+	defer func() {
+		if err = rdb.Close(); err != nil {
+			data.log.Error(err)
+		}
+	}()
+
+	plugManager, migrator, err := initPlugins(data, true)
+	if err != nil {
+		return err
+	}
+
+	if err = checkMigrations(data, migrator); err != nil {
+		return err
+	}
+
+	initTheme(data)
+
+	jobQueue := initQueue(data, rdb, plugManager)
+
+	mailManager, err := initMail(data, jobQueue)
+	if err != nil {
+		return err
+	}
+
+	s := spring.New(data.cfg, data.log)
+	s.Mail = mailManager
+	s.Queue = jobQueue
+
+	scheduler, err := initSchedule(data, rdb, plugManager)
+	if err != nil {
+		return err
+	}
+
+	data.log.Infoln("plugin manager: RegisterAll")
+	plugManager.RegisterAll(s)
+
+	return scheduler.Run()
+}
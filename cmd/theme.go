@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	themedb "github.com/iagapie/go-spring/modules/cms/theme/db"
+	"github.com/iagapie/go-spring/modules/sys/datasource"
+	"time"
+)
+
+const themeSyncInterval = 30 * time.Second
+
+// initTheme activates the theme persisted by theme:use, falling back to cms.active_theme.
+func initTheme(data *__data) theme.Storage {
+	data.log.Infoln("theme initializing")
+	theme.SetThemesPath(themesPath(data))
+	theme.SetDatasource(datasource.NewFile(data.log))
+	theme.SetActiveTheme(data.cfg.CMS.ActiveTheme)
+
+	storage := themedb.NewStorage(data.db, data.log.Entry)
+	if err := theme.Sync(context.Background(), storage); err != nil {
+		data.log.Warnf("theme: %v, using %s", err, data.cfg.CMS.ActiveTheme)
+	}
+
+	return storage
+}
+
+func themesPath(data *__data) string {
+	return fmt.Sprintf("%s/frontend", data.cfg.CMS.ThemesPath)
+}
+
+// themeContents loads the plugins only when the database content of themes is wanted.
+func themeContents(data *__data, withContent bool) (theme.ContentMap, error) {
+	if !withContent {
+		return nil, nil
+	}
+
+	plugManager, _, err := initPlugins(data, false)
+	if err != nil {
+		return nil, err
+	}
+	return theme.Contents(plugManager.Enabled()), nil
+}
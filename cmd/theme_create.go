@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+var ThemeCreate = &cli.Command{
+	Name:      "theme:create",
+	Usage:     "Create a new frontend theme",
+	ArgsUsage: "name",
+	Action:    runThemeCreate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "author",
+			Aliases: []string{"a"},
+			Usage:   "Theme author",
+		},
+		&cli.StringFlag{
+			Name:    "parent",
+			Aliases: []string{"p"},
+			Usage:   "Create a child theme inheriting everything it does not override from this theme",
+		},
+	},
+}
+
+func runThemeCreate(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: theme:create name")
+	}
+
+	var cfg config.Cfg
+	if err := helper.ReadConfig(&cfg, ctx.StringSlice("config")...); err != nil {
+		return err
+	}
+
+	files, err := scaffold.CreateTheme(fmt.Sprintf("%s/frontend", cfg.CMS.ThemesPath), ctx.Args().First(), ctx.String("author"), ctx.String("parent"))
+	printCreated(files)
+	return err
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/urfave/cli/v2"
+	"os"
+)
+
+var ThemeExport = &cli.Command{
+	Name:      "theme:export",
+	Usage:     "Export a frontend theme to a zip archive",
+	ArgsUsage: "name out.zip",
+	Action:    runThemeExport,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "content",
+			Usage: "Include the theme content plugins keep in the database",
+		},
+	},
+}
+
+func runThemeExport(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("usage: theme:export name out.zip")
+	}
+
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	initTheme(data)
+
+	name := ctx.Args().Get(0)
+	t, ok := theme.Get(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", theme.ErrNotFound, name)
+	}
+
+	contents, err := themeContents(data, ctx.Bool("content"))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	if err = theme.Export(context.Background(), f, t, contents); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	data.log.Infof("theme %s was exported to %s", name, f.Name())
+	return nil
+}
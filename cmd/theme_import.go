@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/urfave/cli/v2"
+	"os"
+)
+
+var ThemeImport = &cli.Command{
+	Name:      "theme:import",
+	Usage:     "Import a frontend theme from a zip archive",
+	ArgsUsage: "in.zip",
+	Action:    runThemeImport,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "name",
+			Aliases: []string{"n"},
+			Usage:   "Theme directory, defaults to the one in the archive",
+		},
+		&cli.BoolFlag{
+			Name:    "force",
+			Aliases: []string{"f"},
+			Usage:   "Replace the theme when it already exists",
+		},
+		&cli.BoolFlag{
+			Name:  "content",
+			Usage: "Import the theme content stored in the archive into the database",
+		},
+	},
+}
+
+func runThemeImport(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: theme:import in.zip")
+	}
+
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	contents, err := themeContents(data, ctx.Bool("content"))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	name, err := theme.Import(context.Background(), f, fi.Size(), themesPath(data), theme.ImportOptions{
+		Name:    ctx.String("name"),
+		Force:   ctx.Bool("force"),
+		Content: contents,
+	})
+	if err != nil {
+		return err
+	}
+
+	data.log.Infof("theme %s was imported", name)
+	return nil
+}
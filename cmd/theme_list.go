@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/urfave/cli/v2"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+var ThemeList = &cli.Command{
+	Name:   "theme:list",
+	Usage:  "List frontend themes and show the active one",
+	Action: runThemeList,
+}
+
+func runThemeList(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	initTheme(data)
+
+	themes := theme.Themes()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DIR\tNAME\tAUTHOR\tACTIVE\tCHAIN\tDESCRIPTION")
+	for _, name := range names {
+		t := themes[name]
+
+		active := ""
+		if t.IsActive() {
+			active = "*"
+		}
+
+		cfg, err := t.Cfg()
+		if err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t%s\t-\tinvalid theme.yml: %v\n", name, active, err)
+			continue
+		}
+
+		chain, err := t.Chain()
+		dirs := make([]string, 0, len(chain))
+		for _, c := range chain {
+			dirs = append(dirs, c.Dir())
+		}
+		description := cfg.Description
+		if err != nil {
+			description = err.Error()
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name, cfg.Name, cfg.Author, active, strings.Join(dirs, " -> "), description)
+	}
+	return w.Flush()
+}
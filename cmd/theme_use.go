@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/urfave/cli/v2"
+)
+
+var ThemeUse = &cli.Command{
+	Name:      "theme:use",
+	Usage:     "Make a frontend theme the active one",
+	ArgsUsage: "name",
+	Action:    runThemeUse,
+}
+
+func runThemeUse(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: theme:use name")
+	}
+
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	name := ctx.Args().First()
+	if err = theme.Use(context.Background(), initTheme(data), name); err != nil {
+		return err
+	}
+
+	data.log.Infof("theme %s is active", name)
+	return nil
+}
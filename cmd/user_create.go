@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/iagapie/go-spring/modules/backend/user"
+	"github.com/iagapie/go-spring/modules/sys/queue"
 	"github.com/urfave/cli/v2"
 )
 
@@ -30,6 +31,10 @@ var UserCreate = &cli.Command{
 			Usage:    "User password",
 			Required: true,
 		},
+		&cli.BoolFlag{
+			Name:  "verified",
+			Usage: "Mark the user email as verified instead of sending a verification email",
+		},
 	},
 }
 
@@ -57,5 +62,31 @@ func runUserCreate(ctx *cli.Context) error {
 	}
 
 	data.log.Infof("user %s <%s> was created with UUID %s", dto.Name, dto.Email, id)
+
+	if ctx.Bool("verified") {
+		return data.userService.VerifyEmail(context.Background(), id)
+	}
+
+	initTheme(data)
+
+	rdb := initRedis(data)
+	defer func() {
+		if err = rdb.Close(); err != nil {
+			data.log.Error(err)
+		}
+	}()
+
+	mailManager, err := initMail(data, queue.New(data.cfg.Queue, rdb, data.log))
+	if err != nil {
+		return err
+	}
+
+	accountService := initAccount(data, rdb, mailManager, nil)
+
+	if err = accountService.SendVerification(context.Background(), id); err != nil {
+		return err
+	}
+
+	data.log.Infof("verification email was sent to <%s>", dto.Email)
 	return nil
 }
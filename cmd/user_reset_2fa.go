@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"github.com/urfave/cli/v2"
+)
+
+var UserResetTwoFactor = &cli.Command{
+	Name:   "user:reset-2fa",
+	Usage:  "Reset backend user two-factor authentication",
+	Action: runUserResetTwoFactor,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "email",
+			Aliases:  []string{"e"},
+			Usage:    "User email",
+			Required: true,
+		},
+	},
+}
+
+func runUserResetTwoFactor(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	u, err := data.userService.GetByEmail(context.Background(), ctx.String("email"))
+	if err != nil {
+		return err
+	}
+
+	if err = data.userService.ResetTwoFactor(context.Background(), u.UUID); err != nil {
+		return err
+	}
+
+	data.log.Infof("two-factor authentication of user %s <%s> was reset", u.Name, u.Email)
+	return nil
+}
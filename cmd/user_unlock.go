@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"github.com/iagapie/go-spring/modules/backend/auth"
+	"github.com/urfave/cli/v2"
+)
+
+var UserUnlock = &cli.Command{
+	Name:   "user:unlock",
+	Usage:  "Unlock backend user after too many failed sign-in attempts",
+	Action: runUserUnlock,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "email",
+			Aliases:  []string{"e"},
+			Usage:    "User email",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "ip",
+			Usage: "Also unlock this client IP address",
+		},
+	},
+}
+
+func runUserUnlock(ctx *cli.Context) error {
+	data, err := initData(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.db.Close()
+
+	rdb := initRedis(data)
+	defer func() {
+		if err = rdb.Close(); err != nil {
+			data.log.Error(err)
+		}
+	}()
+
+	email := ctx.String("email")
+	if err = auth.NewThrottle(data.cfg.Auth.Throttle, rdb).Unlock(context.Background(), ctx.String("ip"), email); err != nil {
+		return err
+	}
+
+	data.log.Infof("user <%s> was unlocked", email)
+	return nil
+}
@@ -5,21 +5,35 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-redis/cache/v8"
-	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/backend/account"
 	"github.com/iagapie/go-spring/modules/backend/auth"
+	authdb "github.com/iagapie/go-spring/modules/backend/auth/db"
+	backendmedia "github.com/iagapie/go-spring/modules/backend/media"
+	"github.com/iagapie/go-spring/modules/backend/redirect"
+	redirectdb "github.com/iagapie/go-spring/modules/backend/redirect/db"
+	backendschedule "github.com/iagapie/go-spring/modules/backend/schedule"
+	backendtheme "github.com/iagapie/go-spring/modules/backend/theme"
 	"github.com/iagapie/go-spring/modules/backend/user"
 	"github.com/iagapie/go-spring/modules/cms/component"
 	"github.com/iagapie/go-spring/modules/cms/controller"
+	cmsrouter "github.com/iagapie/go-spring/modules/cms/router"
 	"github.com/iagapie/go-spring/modules/cms/theme"
-	"github.com/iagapie/go-spring/modules/sys/datasource"
+	"github.com/iagapie/go-spring/modules/sys/asset"
+	"github.com/iagapie/go-spring/modules/sys/media"
 	"github.com/iagapie/go-spring/modules/sys/middleware"
-	"github.com/iagapie/go-spring/modules/sys/plugin"
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/iagapie/go-spring/modules/sys/token"
 	"github.com/iagapie/go-spring/modules/sys/view"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
 	"github.com/urfave/cli/v2"
+	"strings"
+	"time"
+)
+
+const (
+	redirectSyncInterval = 30 * time.Second
+	pagesSyncInterval    = 5 * time.Second
 )
 
 var Web = &cli.Command{
@@ -28,6 +42,12 @@ var Web = &cli.Command{
 	Description: `Spring CMS web server is the only thing you need to run,
 and it takes care of all the other things for you`,
 	Action: runWeb,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "schedule",
+			Usage: "Run the scheduled tasks inside the web server, same as schedule.web in the configuration",
+		},
+	},
 }
 
 func runWeb(ctx *cli.Context) error {
@@ -37,12 +57,7 @@ func runWeb(ctx *cli.Context) error {
 	}
 	defer data.db.Close()
 
-	data.log.Infoln("redis initializing")
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     data.cfg.Redis.Addr,
-		Password: data.cfg.Redis.Password,
-		DB:       0,
-	})
+	rdb := initRedis(data)
 	defer func() {
 		if err = rdb.Close(); err != nil {
 			log.Error(err)
@@ -55,28 +70,88 @@ func runWeb(ctx *cli.Context) error {
 	})
 
 	data.log.Infoln("token manager initializing")
-	tokenManager := token.New(token.WithJWTKeys(data.cfg.JWT.SigningKeys))
+	tokenManager := token.New(token.WithJWTKeys(data.cfg.JWT.SigningKeys), token.WithAudience(token.AudienceAccess))
 
 	data.log.Infoln("auth service initializing")
-	authService := auth.NewService(data.cfg.JWT.TTL, data.userService, redisCache, tokenManager, data.log.Entry)
+	authService := auth.NewService(
+		data.cfg.JWT.TTL,
+		data.userService,
+		redisCache,
+		rdb,
+		tokenManager,
+		auth.NewThrottle(data.cfg.Auth.Throttle, rdb),
+		authdb.NewAttemptStorage(data.db, data.log.Entry),
+		data.log.Entry,
+	)
+
+	plugManager, migrator, err := initPlugins(data, true)
+	if err != nil {
+		return err
+	}
+
+	if err = checkMigrations(data, migrator); err != nil {
+		return err
+	}
 
-	data.log.Infoln("plugin manager initializing")
-	plugManager, err := plugin.New(data.cfg.CMS.PluginsPath, data.log)
+	jobQueue := initQueue(data, rdb, plugManager)
+
+	mailManager, err := initMail(data, jobQueue)
 	if err != nil {
 		return err
 	}
 
+	accountService := initAccount(data, rdb, mailManager, authService)
+
 	data.log.Infoln("spring (echo) framework initializing")
 	s := spring.New(data.cfg, data.log)
+	s.Mail = mailManager
+	s.Queue = jobQueue
 	view.Add("routeURL", s.Reverse)
 
-	data.log.Infoln("theme initializing")
-	theme.SetThemesPath(fmt.Sprintf("%s/frontend", data.cfg.CMS.ThemesPath))
-	theme.SetDatasource(datasource.NewFile(data.log))
-	theme.SetActiveTheme(data.cfg.CMS.ActiveTheme)
+	scheduler, err := initSchedule(data, rdb, plugManager)
+	if err != nil {
+		return err
+	}
+
+	themeStorage := initTheme(data)
 
-	for _, t := range theme.Themes() {
-		s.Frontend.Static(t.Assets())
+	s.Assets = asset.NewCombiner(data.cfg.Assets, data.log)
+
+	// the theme is resolved per request, themes created or imported while running are served as well
+	mgh := []string{echo.GET, echo.HEAD}
+	s.Frontend.Match(mgh, fmt.Sprintf("%s/%s/:file", theme.AssetsRoute, asset.CombineDir), func(c echo.Context) error {
+		t, ok := theme.Get(c.Param("theme"))
+		if !ok {
+			return echo.ErrNotFound
+		}
+		return s.Assets.Serve(t.Dir())(c)
+	})
+	s.Frontend.Match(mgh, theme.AssetsRoute+"/*", func(c echo.Context) error {
+		t, ok := theme.Get(c.Param("theme"))
+		if !ok {
+			return echo.ErrNotFound
+		}
+		_, path := t.Assets()
+		return asset.Static(path, data.cfg.Assets)(c)
+	})
+
+	data.log.Infoln("media storage initializing")
+	mediaStorage, err := media.New(data.cfg.Media)
+	if err != nil {
+		return err
+	}
+	s.Media = media.NewManager(mediaStorage, data.cfg.Media, data.log)
+	view.Add("media", s.Media.URL)
+	view.Add("resize", s.Media.ResizeURL)
+	if data.cfg.Media.Driver == media.DriverLocal && strings.HasPrefix(data.cfg.Media.URL, "/") {
+		s.Frontend.Match(mgh, strings.TrimSuffix(data.cfg.Media.URL, "/")+"/*", asset.Static(data.cfg.Media.Path, data.cfg.Assets))
+	}
+
+	theme.RegisterPlugins(plugManager.Enabled())
+	for _, i := range plugManager.Enabled() {
+		if fsys := i.AssetsFS(); fsys != nil {
+			s.Frontend.Match(mgh, i.AssetsURI()+"/*", asset.StaticFS(fsys, data.cfg.Assets))
+		}
 	}
 
 	data.log.Infoln("plugin manager: RegisterAll")
@@ -91,10 +166,16 @@ func runWeb(ctx *cli.Context) error {
 
 	userContextKey := "user"
 	userTransformFunc := func(ctx context.Context, item interface{}) (interface{}, error) {
-		return data.userService.GetByUUID(ctx, item.(string))
+		id, ok := item.(string)
+		if !ok {
+			return nil, echo.ErrUnauthorized
+		}
+		return data.userService.GetByUUID(ctx, id)
 	}
 	userMiddleware := middleware.Transformer(data.cfg.JWT.ContextKey, userContextKey, userTransformFunc)
-	jwtMiddleware := middleware.JWT(data.cfg.JWT, tokenManager)
+	jwtMiddleware := middleware.JWT(data.cfg.JWT, tokenManager, authService.Revoked)
+	s.Backend.JWTMiddleware = jwtMiddleware
+	s.Backend.UserMiddleware = userMiddleware
 
 	data.log.Infoln("backend user handler initializing")
 	userHandler := &user.Handler{
@@ -105,6 +186,55 @@ func runWeb(ctx *cli.Context) error {
 	}
 	userHandler.Register(s.Backend)
 
+	data.log.Infoln("backend account handler initializing")
+	accountHandler := &account.Handler{
+		Service:        accountService,
+		JWTMiddleware:  jwtMiddleware,
+		UserMiddleware: userMiddleware,
+		UserContextKey: userContextKey,
+	}
+	accountHandler.Register(s.Backend)
+
+	data.log.Infoln("backend schedule handler initializing")
+	scheduleHandler := &backendschedule.Handler{
+		Scheduler:      scheduler,
+		JWTMiddleware:  jwtMiddleware,
+		UserMiddleware: userMiddleware,
+	}
+	scheduleHandler.Register(s.Backend)
+
+	data.log.Infoln("backend theme handler initializing")
+	themeHandler := &backendtheme.Handler{
+		Storage:        themeStorage,
+		ThemesPath:     themesPath(data),
+		Contents:       theme.Contents(plugManager.Enabled()),
+		JWTMiddleware:  jwtMiddleware,
+		UserMiddleware: userMiddleware,
+	}
+	themeHandler.Register(s.Backend)
+
+	data.log.Infoln("backend media handler initializing")
+	mediaHandler := &backendmedia.Handler{
+		Manager:        s.Media,
+		JWTMiddleware:  jwtMiddleware,
+		UserMiddleware: userMiddleware,
+	}
+	mediaHandler.Register(s.Backend)
+
+	data.log.Infoln("redirect manager initializing")
+	redirectManager := redirect.NewManager(redirectdb.NewStorage(data.db, data.log.Entry), data.log.Entry)
+	if err = redirectManager.Load(context.Background()); err != nil {
+		return err
+	}
+
+	data.log.Infoln("backend redirect handler initializing")
+	redirectHandler := &redirect.Handler{
+		Manager:        redirectManager,
+		JWTMiddleware:  jwtMiddleware,
+		UserMiddleware: userMiddleware,
+	}
+	redirectHandler.Register(s.Backend)
+
 	data.log.Infoln("cms controller initializing")
 	s.HTTPErrorHandler = func(err error, c echo.Context) {
 		if errors.Is(err, user.ErrRecordNotFound) {
@@ -118,16 +248,43 @@ func runWeb(ctx *cli.Context) error {
 	s.Frontend.Any("/", func(c echo.Context) error {
 		ctr := controller.New(s, compManager)
 		return ctr.Run(c)
-	})
+	}, redirectManager.Middleware)
 
 	s.Frontend.Any("/*", func(c echo.Context) error {
 		ctr := controller.New(s, compManager)
 		return ctr.Run(c)
-	})
+	}, redirectManager.Middleware)
 
 	data.log.Infoln("plugin manager: RoutesAll")
 	plugManager.RoutesAll(s.Frontend, s.Backend)
 
+	themeCtx, cancelTheme := context.WithCancel(context.Background())
+	defer cancelTheme()
+	go theme.Watch(themeCtx, themeStorage, themeSyncInterval, data.log)
+	go cmsrouter.Watch(themeCtx, pagesSyncInterval)
+
+	// the hits of the last interval are saved once the server stopped, before the database is closed
+	redirectCtx, cancelRedirect := context.WithCancel(context.Background())
+	redirectDone := make(chan struct{})
+	defer func() {
+		cancelRedirect()
+		<-redirectDone
+	}()
+	go func() {
+		defer close(redirectDone)
+		redirectManager.Watch(redirectCtx, redirectSyncInterval)
+	}()
+
+	if data.cfg.Schedule.Web || ctx.Bool("schedule") {
+		scheduleCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			if err := scheduler.Work(scheduleCtx); err != nil {
+				data.log.Error(err)
+			}
+		}()
+	}
+
 	if err = s.Run(); err != nil {
 		return fmt.Errorf("error occurred while running HTTP runner: %v", err)
 	}
@@ -9,11 +9,17 @@ import (
 
 var cfgFiles = []string{
 	"./configs/app",
+	"./configs/assets",
+	"./configs/auth",
 	"./configs/cms",
 	"./configs/cors",
 	"./configs/db",
+	"./configs/mail",
+	"./configs/media",
+	"./configs/queue",
 	"./configs/jwt",
 	"./configs/redis",
+	"./configs/schedule",
 }
 
 func main() {
@@ -23,6 +29,26 @@ func main() {
 	app.Commands = []*cli.Command{
 		cmd.Web,
 		cmd.UserCreate,
+		cmd.UserResetTwoFactor,
+		cmd.UserUnlock,
+		cmd.QueueWork,
+		cmd.ScheduleRun,
+		cmd.MigrateUp,
+		cmd.MigrateDown,
+		cmd.MigrateStatus,
+		cmd.MigrateCreate,
+		cmd.PluginList,
+		cmd.PluginEnable,
+		cmd.PluginDisable,
+		cmd.PluginUninstall,
+		cmd.CreatePlugin,
+		cmd.CreateComponent,
+		cmd.ThemeList,
+		cmd.ThemeCreate,
+		cmd.ThemeUse,
+		cmd.ThemeExport,
+		cmd.ThemeImport,
+		cmd.AssetsBuild,
 	}
 
 	defaultFlags := []cli.Flag{
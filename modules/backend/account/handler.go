@@ -0,0 +1,118 @@
+package account
+
+import (
+	"errors"
+	"github.com/iagapie/go-spring/modules/backend/user"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+const (
+	forgotPasswordURL = "/api/password/forgot"
+	resetPasswordURL  = "/api/password/reset"
+	verifyEmailURL    = "/api/email/verify"
+	resendEmailURL    = "/api/email/verify/resend"
+)
+
+type Handler struct {
+	Service        Service
+	JWTMiddleware  echo.MiddlewareFunc
+	UserMiddleware echo.MiddlewareFunc
+	UserContextKey string
+}
+
+func (h *Handler) Register(b *spring.Backend) {
+	mp := []string{echo.POST, echo.OPTIONS}
+	b.Match(mp, forgotPasswordURL, h.forgotPassword)[0].Name = "backend-password-forgot"
+	b.Match(mp, resetPasswordURL, h.resetPassword)[0].Name = "backend-password-reset"
+	b.Match(mp, verifyEmailURL, h.verifyEmail)[0].Name = "backend-email-verify"
+	b.Match(mp, resendEmailURL, h.resendVerification, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-email-verify-resend"
+}
+
+func (h *Handler) forgotPassword(c echo.Context) error {
+	c.Logger().Info("BACKEND FORGOT PASSWORD HANDLER")
+
+	var dto ForgotPasswordDTO
+
+	c.Logger().Debug("bind ForgotPasswordDTO")
+	if err := c.Bind(&dto); err != nil {
+		return err
+	}
+
+	c.Logger().Debug("validate ForgotPasswordDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	if err := h.Service.ForgotPassword(c.Request().Context(), dto, c.RealIP()); err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) resetPassword(c echo.Context) error {
+	c.Logger().Info("BACKEND RESET PASSWORD HANDLER")
+
+	var dto ResetPasswordDTO
+
+	c.Logger().Debug("bind ResetPasswordDTO")
+	if err := c.Bind(&dto); err != nil {
+		return err
+	}
+
+	c.Logger().Debug("validate ResetPasswordDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	if err := h.Service.ResetPassword(c.Request().Context(), dto); err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) verifyEmail(c echo.Context) error {
+	c.Logger().Info("BACKEND VERIFY EMAIL HANDLER")
+
+	var dto VerifyEmailDTO
+
+	c.Logger().Debug("bind VerifyEmailDTO")
+	if err := c.Bind(&dto); err != nil {
+		return err
+	}
+
+	c.Logger().Debug("validate VerifyEmailDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	if err := h.Service.VerifyEmail(c.Request().Context(), dto); err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) resendVerification(c echo.Context) error {
+	c.Logger().Info("BACKEND RESEND EMAIL VERIFICATION HANDLER")
+
+	u := c.Get(h.UserContextKey).(user.User)
+	if err := h.Service.SendVerification(c.Request().Context(), u.UUID); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toHTTPError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidToken):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, ErrInvalidToken.Error()).SetInternal(err)
+	case errors.Is(err, ErrTooManyRequests):
+		return echo.NewHTTPError(http.StatusTooManyRequests, ErrTooManyRequests.Error()).SetInternal(err)
+	}
+	return err
+}
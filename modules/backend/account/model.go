@@ -0,0 +1,23 @@
+package account
+
+type ForgotPasswordDTO struct {
+	Email string `json:"email,omitempty" validate:"required,email,min=3,max=255"`
+}
+
+type ResetPasswordDTO struct {
+	Token          string `json:"token,omitempty" validate:"required"`
+	Password       string `json:"password,omitempty" validate:"required,min=8,max=64"`
+	RepeatPassword string `json:"repeat_password,omitempty" validate:"eqfield=Password"`
+}
+
+type VerifyEmailDTO struct {
+	Token string `json:"token,omitempty" validate:"required"`
+}
+
+type mailVars struct {
+	AppName string
+	Name    string
+	Email   string
+	URL     string
+	TTL     string
+}
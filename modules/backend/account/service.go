@@ -0,0 +1,278 @@
+package account
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/backend/user"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/mail"
+	"github.com/iagapie/go-spring/modules/sys/token"
+	"github.com/sirupsen/logrus"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	actionResetPassword = "reset_password"
+	actionVerifyEmail   = "verify_email"
+
+	resetPasswordPath = "/reset-password"
+	verifyEmailPath   = "/verify-email"
+
+	forgotPrefix   = "forgot:"
+	cooldownPrefix = "account-mail:"
+)
+
+var (
+	ErrInvalidToken    = errors.New("token is invalid or has expired")
+	ErrTooManyRequests = errors.New("too many requests, try again later")
+)
+
+type (
+	Service interface {
+		ForgotPassword(ctx context.Context, dto ForgotPasswordDTO, ip string) error
+		ResetPassword(ctx context.Context, dto ResetPasswordDTO) error
+		SendVerification(ctx context.Context, uuid string) error
+		VerifyEmail(ctx context.Context, dto VerifyEmailDTO) error
+	}
+
+	// Sessions signs a user out everywhere after the password was reset.
+	Sessions interface {
+		RevokeSessions(ctx context.Context, uuid string) error
+	}
+
+	service struct {
+		cfg          config.Cfg
+		userService  user.Service
+		tokenManager token.Token
+		mail         *mail.Manager
+		sessions     Sessions
+		rdb          redis.UniversalClient
+		log          *logrus.Entry
+	}
+)
+
+// NewService expects a token manager with the token.AudienceAccount audience, so that the emailed tokens
+// can't be used as access tokens. sessions may be nil when nobody can be signed in, e.g. in the console.
+func NewService(
+	cfg config.Cfg,
+	userService user.Service,
+	tokenManager token.Token,
+	mail *mail.Manager,
+	sessions Sessions,
+	rdb redis.UniversalClient,
+	log *logrus.Entry,
+) Service {
+	return &service{
+		cfg:          cfg,
+		userService:  userService,
+		tokenManager: tokenManager,
+		mail:         mail,
+		sessions:     sessions,
+		rdb:          rdb,
+		log:          log,
+	}
+}
+
+// ForgotPassword emails a reset link when the address belongs to a user. Unknown addresses are not reported
+// so that the endpoint can't be used to find out which accounts exist.
+func (s *service) ForgotPassword(ctx context.Context, dto ForgotPasswordDTO, ip string) error {
+	if err := s.limit(ctx, ip); err != nil {
+		return fmt.Errorf("forgot password: %w", err)
+	}
+
+	u, err := s.userService.GetByEmail(ctx, dto.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrRecordNotFound) {
+			s.log.Debugf("forgot password: %s not found", dto.Email)
+			return nil
+		}
+		return fmt.Errorf("forgot password: %w", err)
+	}
+
+	ttl := s.cfg.Auth.PasswordResetTTL
+	t, err := s.tokenManager.Create(ttl, map[string]string{
+		"act": actionResetPassword,
+		"sub": u.UUID,
+		"fp":  fingerprint(u.Password),
+	})
+	if err != nil {
+		return fmt.Errorf("forgot password: %w", err)
+	}
+
+	if err = s.send(ctx, u, "reset_password", resetPasswordPath, t, ttl); err != nil {
+		return fmt.Errorf("forgot password: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword sets the new password. The token carries a fingerprint of the current password hash,
+// so it stops being valid as soon as it has been used once.
+func (s *service) ResetPassword(ctx context.Context, dto ResetPasswordDTO) error {
+	claims, err := s.claims(dto.Token, actionResetPassword)
+	if err != nil {
+		return fmt.Errorf("reset password: %w", err)
+	}
+
+	u, err := s.userService.GetByUUID(ctx, claims["sub"])
+	if err != nil {
+		if errors.Is(err, user.ErrRecordNotFound) {
+			return fmt.Errorf("reset password: %w", ErrInvalidToken)
+		}
+		return fmt.Errorf("reset password: %w", err)
+	}
+	if claims["fp"] != fingerprint(u.Password) {
+		return fmt.Errorf("reset password: %w", ErrInvalidToken)
+	}
+
+	if err = s.userService.SetPassword(ctx, u.UUID, dto.Password); err != nil {
+		return fmt.Errorf("reset password: %w", err)
+	}
+
+	// whoever had the old password may still be signed in
+	if s.sessions != nil {
+		if err = s.sessions.RevokeSessions(ctx, u.UUID); err != nil {
+			return fmt.Errorf("reset password: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *service) SendVerification(ctx context.Context, uuid string) error {
+	u, err := s.userService.GetByUUID(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("send verification: %w", err)
+	}
+	if u.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	ttl := s.cfg.Auth.EmailVerifyTTL
+	t, err := s.tokenManager.Create(ttl, map[string]string{
+		"act":   actionVerifyEmail,
+		"sub":   u.UUID,
+		"email": u.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("send verification: %w", err)
+	}
+
+	if err = s.send(ctx, u, "verify_email", verifyEmailPath, t, ttl); err != nil {
+		return fmt.Errorf("send verification: %w", err)
+	}
+	return nil
+}
+
+func (s *service) VerifyEmail(ctx context.Context, dto VerifyEmailDTO) error {
+	claims, err := s.claims(dto.Token, actionVerifyEmail)
+	if err != nil {
+		return fmt.Errorf("verify email: %w", err)
+	}
+
+	u, err := s.userService.GetByUUID(ctx, claims["sub"])
+	if err != nil {
+		if errors.Is(err, user.ErrRecordNotFound) {
+			return fmt.Errorf("verify email: %w", ErrInvalidToken)
+		}
+		return fmt.Errorf("verify email: %w", err)
+	}
+	if !strings.EqualFold(claims["email"], u.Email) {
+		return fmt.Errorf("verify email: %w", ErrInvalidToken)
+	}
+
+	if err = s.userService.VerifyEmail(ctx, u.UUID); err != nil {
+		return fmt.Errorf("verify email: %w", err)
+	}
+	return nil
+}
+
+// send emails the link, nothing is sent when the user got an email in the last MailCooldown
+// so that the endpoints can't be used to flood a mailbox.
+func (s *service) send(ctx context.Context, u user.User, template, path, t string, ttl time.Duration) error {
+	if s.cfg.Auth.MailCooldown > 0 {
+		ok, err := s.rdb.SetNX(ctx, cooldownPrefix+u.UUID, 1, s.cfg.Auth.MailCooldown).Result()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.log.Debugf("%s email to %s skipped: cooldown", template, u.Email)
+			return nil
+		}
+	}
+
+	vars := mailVars{
+		AppName: s.cfg.App.Name,
+		Name:    u.Name,
+		Email:   u.Email,
+		URL:     s.link(path, t),
+		TTL:     ttl.String(),
+	}
+	return s.mail.SendTemplate(ctx, template, vars, netmail.Address{Name: u.Name, Address: u.Email})
+}
+
+// limit counts the forgot password requests of the IP, the counter starts with the first request of the window.
+func (s *service) limit(ctx context.Context, ip string) error {
+	if s.cfg.Auth.ForgotIPMaxAttempts <= 0 {
+		return nil
+	}
+
+	key := forgotPrefix + ip
+	n, err := s.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		if err = s.rdb.Expire(ctx, key, s.cfg.Auth.ForgotWindow).Err(); err != nil {
+			return err
+		}
+	}
+	if n > int64(s.cfg.Auth.ForgotIPMaxAttempts) {
+		return ErrTooManyRequests
+	}
+	return nil
+}
+
+func (s *service) link(path, t string) string {
+	return fmt.Sprintf(
+		"%s%s%s?%s",
+		strings.TrimRight(s.cfg.App.URL, "/"),
+		s.cfg.CMS.BackendURI,
+		path,
+		url.Values{"token": []string{t}}.Encode(),
+	)
+}
+
+func (s *service) claims(t, action string) (map[string]string, error) {
+	data, err := s.tokenManager.Validate(t)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	raw, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	claims := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if str, ok := v.(string); ok {
+			claims[k] = str
+		}
+	}
+
+	if claims["act"] != action || claims["sub"] == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func fingerprint(hash string) string {
+	sum := sha256.Sum256([]byte(hash))
+	return hex.EncodeToString(sum[:8])
+}
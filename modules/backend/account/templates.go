@@ -0,0 +1,18 @@
+package account
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed mail/*.html mail/*.txt
+var templates embed.FS
+
+// Templates returns the built-in mail templates. Themes override them with files of the same name in their "mail" directory.
+func Templates() fs.FS {
+	sub, err := fs.Sub(templates, "mail")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
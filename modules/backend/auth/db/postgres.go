@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/backend/auth"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+var _ auth.AttemptStorage = &storage{}
+
+type storage struct {
+	db  *postgresdb.Database
+	log *logrus.Entry
+}
+
+func NewAttemptStorage(postgres *postgresdb.Database, log *logrus.Entry) auth.AttemptStorage {
+	return &storage{
+		db:  postgres,
+		log: log,
+	}
+}
+
+func (s *storage) Create(ctx context.Context, model auth.Attempt) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+
+	s.log.Tracef("Created auth attempt: %d.\n", model.ID)
+
+	return nil
+}
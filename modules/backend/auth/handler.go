@@ -1,14 +1,22 @@
 package auth
 
 import (
+	"errors"
+	"github.com/go-redis/cache/v8"
+	"github.com/iagapie/go-spring/modules/backend/user"
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/labstack/echo/v4"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 const (
-	signInURL  = "/api/sign-in"
-	refreshURL = "/api/refresh"
+	signInURL          = "/api/sign-in"
+	signInTwoFactorURL = "/api/sign-in/2fa"
+	refreshURL         = "/api/refresh"
+
+	headerRetryAfter = "Retry-After"
 )
 
 type Handler struct {
@@ -18,6 +26,7 @@ type Handler struct {
 func (h *Handler) Register(b *spring.Backend) {
 	mp := []string{echo.POST, echo.OPTIONS}
 	b.Match(mp, signInURL, h.signIn)[0].Name = "backend-sign-in"
+	b.Match(mp, signInTwoFactorURL, h.signInTwoFactor)[0].Name = "backend-sign-in-2fa"
 	b.Match(mp, refreshURL, h.refresh)[0].Name = "backend-refresh"
 }
 
@@ -36,11 +45,34 @@ func (h *Handler) signIn(c echo.Context) error {
 		return err
 	}
 
-	r, err := h.Service.Auth(c.Request().Context(), dto)
+	r, err := h.Service.Auth(c.Request().Context(), dto, client(c))
 	if err != nil {
+		return toHTTPError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &r)
+}
+
+func (h *Handler) signInTwoFactor(c echo.Context) error {
+	c.Logger().Info("BACKEND SIGN IN 2FA HANDLER")
+
+	var dto TwoFactorSignInDTO
+
+	c.Logger().Debug("bind TwoFactorSignInDTO")
+	if err := c.Bind(&dto); err != nil {
 		return err
 	}
 
+	c.Logger().Debug("validate TwoFactorSignInDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	r, err := h.Service.AuthTwoFactor(c.Request().Context(), dto, client(c))
+	if err != nil {
+		return toHTTPError(c, err)
+	}
+
 	return c.JSON(http.StatusOK, &r)
 }
 
@@ -66,3 +98,21 @@ func (h *Handler) refresh(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, &r)
 }
+
+func client(c echo.Context) Client {
+	return Client{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+}
+
+func toHTTPError(c echo.Context, err error) error {
+	if lErr, ok := IsLocked(err); ok {
+		c.Response().Header().Set(headerRetryAfter, strconv.Itoa(int(math.Ceil(lErr.RetryAfter.Seconds()))))
+		return echo.NewHTTPError(http.StatusTooManyRequests, lErr.Error()).SetInternal(err)
+	}
+	if errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, ErrChallengeExhausted) || errors.Is(err, user.ErrTwoFactorInvalidCode) || errors.Is(err, user.ErrTwoFactorNotEnabled) {
+		return echo.NewHTTPError(http.StatusUnauthorized).SetInternal(err)
+	}
+	return err
+}
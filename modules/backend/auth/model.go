@@ -1,10 +1,17 @@
 package auth
 
+import "time"
+
 type SignInDTO struct {
 	Email    string `json:"email,omitempty" validate:"required,email,min=3,max=255"`
 	Password string `json:"password,omitempty" validate:"required,min=8,max=64"`
 }
 
+type TwoFactorSignInDTO struct {
+	Token string `json:"token,omitempty" validate:"required,uuid4"`
+	Code  string `json:"code,omitempty" validate:"required,min=6,max=11"`
+}
+
 type RefreshTokenDTO struct {
 	Token string `json:"token,omitempty" validate:"required,uuid4"`
 }
@@ -13,3 +20,47 @@ type TokensResponse struct {
 	AccessToken  string `json:"access_token,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 }
+
+// SignInResponse holds either the session tokens or, for users with two-factor
+// authentication enabled, the challenge token to exchange at the 2fa endpoint.
+type SignInResponse struct {
+	TokensResponse
+	TwoFactor      bool   `json:"two_factor,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
+// Client describes where an authentication attempt came from.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// Attempt is the audit record written for every failed authentication attempt.
+type Attempt struct {
+	ID        uint      `json:"id,omitempty" gorm:"primaryKey"`
+	Email     string    `json:"email,omitempty" gorm:"index;size:255"`
+	UserUUID  string    `json:"user_uuid,omitempty" gorm:"index;size:36"`
+	IP        string    `json:"ip,omitempty" gorm:"index;size:45"`
+	UserAgent string    `json:"user_agent,omitempty" gorm:"size:255"`
+	Reason    string    `json:"reason,omitempty" gorm:"size:64"`
+	CreatedAt time.Time `json:"created_at,omitempty" gorm:"index"`
+}
+
+func (Attempt) TableName() string {
+	return "auth_attempts"
+}
+
+func NewAttempt(email, userUUID, reason string, client Client) Attempt {
+	userAgent := client.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	return Attempt{
+		Email:     email,
+		UserUUID:  userUUID,
+		IP:        client.IP,
+		UserAgent: userAgent,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
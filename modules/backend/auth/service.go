@@ -2,46 +2,165 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-redis/cache/v8"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/iagapie/go-spring/modules/backend/user"
 	"github.com/iagapie/go-spring/modules/sys/config"
 	"github.com/iagapie/go-spring/modules/sys/token"
 	"github.com/sirupsen/logrus"
+	"time"
 )
 
+const (
+	challengeTTL    = 5 * time.Minute
+	challengePrefix = "2fa:"
+	refreshPrefix   = "refresh:"
+	revokedPrefix   = "revoked:"
+	// maxChallengeCodes is how many codes can be tried with a challenge token, then the password is asked again
+	maxChallengeCodes = 5
+
+	reasonLocked             = "locked"
+	reasonInvalidCredentials = "invalid_credentials"
+	reasonInvalidCode        = "invalid_two_factor_code"
+)
+
+var ErrChallengeExhausted = errors.New("too many two-factor codes were tried, sign in again")
+
 type (
 	Service interface {
-		Auth(ctx context.Context, dto SignInDTO) (TokensResponse, error)
+		Auth(ctx context.Context, dto SignInDTO, client Client) (SignInResponse, error)
+		AuthTwoFactor(ctx context.Context, dto TwoFactorSignInDTO, client Client) (TokensResponse, error)
 		RefreshToken(ctx context.Context, dto RefreshTokenDTO) (TokensResponse, error)
+		// RevokeSessions signs the user out everywhere, the refresh tokens are deleted and the access tokens
+		// issued before are rejected by Revoked.
+		RevokeSessions(ctx context.Context, uuid string) error
+		Revoked(ctx context.Context, data interface{}, issuedAt time.Time) bool
 	}
 
 	service struct {
-		duration     config.JWTDuration
-		userService  user.Service
-		redisCache   *cache.Cache
-		tokenManager token.Token
-		log          *logrus.Entry
+		duration       config.JWTDuration
+		userService    user.Service
+		redisCache     *cache.Cache
+		rdb            redis.UniversalClient
+		tokenManager   token.Token
+		throttle       Throttle
+		attemptStorage AttemptStorage
+		log            *logrus.Entry
 	}
 )
 
-func NewService(duration config.JWTDuration, userService user.Service, redisCache *cache.Cache, tokenManager token.Token, log *logrus.Entry) Service {
+func NewService(duration config.JWTDuration, userService user.Service, redisCache *cache.Cache, rdb redis.UniversalClient, tokenManager token.Token, throttle Throttle, attemptStorage AttemptStorage, log *logrus.Entry) Service {
 	return &service{
-		duration:     duration,
-		userService:  userService,
-		redisCache:   redisCache,
-		tokenManager: tokenManager,
-		log:          log,
+		duration:       duration,
+		userService:    userService,
+		redisCache:     redisCache,
+		rdb:            rdb,
+		tokenManager:   tokenManager,
+		throttle:       throttle,
+		attemptStorage: attemptStorage,
+		log:            log,
 	}
 }
 
-func (s *service) Auth(ctx context.Context, dto SignInDTO) (TokensResponse, error) {
+func (s *service) Auth(ctx context.Context, dto SignInDTO, client Client) (SignInResponse, error) {
+	if err := s.throttle.Attempt(ctx, client.IP, dto.Email); err != nil {
+		s.audit(ctx, NewAttempt(dto.Email, "", reasonLocked, client))
+		return SignInResponse{}, fmt.Errorf("authentication: %w", err)
+	}
+
 	u, err := s.userService.GetByEmailAndPassword(ctx, dto.Email, dto.Password)
 	if err != nil {
-		return TokensResponse{}, fmt.Errorf("authentication: %w", err)
+		s.fail(ctx, NewAttempt(dto.Email, "", reasonInvalidCredentials, client))
+		return SignInResponse{}, fmt.Errorf("authentication: %w", err)
+	}
+
+	if err = s.throttle.Release(ctx, client.IP, dto.Email); err != nil {
+		s.log.Error(err)
+	}
+
+	if u.TwoFactorEnabled {
+		challengeToken := uuid.NewString()
+		if err = s.redisCache.Set(&cache.Item{
+			Ctx:   ctx,
+			Key:   challengePrefix + challengeToken,
+			Value: u.UUID,
+			TTL:   challengeTTL,
+		}); err != nil {
+			return SignInResponse{}, fmt.Errorf("authentication: %w", err)
+		}
+		return SignInResponse{TwoFactor: true, ChallengeToken: challengeToken}, nil
+	}
+
+	if err = s.throttle.Reset(ctx, u.Email); err != nil {
+		s.log.Error(err)
+	}
+
+	tokens, err := s.session(ctx, u.UUID)
+	if err != nil {
+		return SignInResponse{}, err
+	}
+	return SignInResponse{TokensResponse: tokens}, nil
+}
+
+func (s *service) AuthTwoFactor(ctx context.Context, dto TwoFactorSignInDTO, client Client) (TokensResponse, error) {
+	key := challengePrefix + dto.Token
+
+	var id string
+	if err := s.redisCache.Get(ctx, key, &id); err != nil {
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
+	}
+	if id == "" {
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %s not found", dto.Token)
+	}
+
+	u, err := s.userService.GetByUUID(ctx, id)
+	if err != nil {
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
+	}
+
+	if err = s.throttle.Attempt(ctx, client.IP, u.Email); err != nil {
+		s.audit(ctx, NewAttempt(u.Email, u.UUID, reasonLocked, client))
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
+	}
+
+	// counted before the code is checked, so concurrent requests can not try more codes
+	codes, err := s.rdb.Incr(ctx, key+":codes").Result()
+	if err != nil {
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
+	}
+	if codes == 1 {
+		if err = s.rdb.Expire(ctx, key+":codes", challengeTTL).Err(); err != nil {
+			return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
+		}
+	}
+	if codes > maxChallengeCodes {
+		if err = s.redisCache.Delete(ctx, key); err != nil {
+			s.log.Error(err)
+		}
+		s.audit(ctx, NewAttempt(u.Email, u.UUID, reasonInvalidCode, client))
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", ErrChallengeExhausted)
+	}
+
+	if err = s.userService.VerifyTwoFactor(ctx, id, dto.Code); err != nil {
+		s.fail(ctx, NewAttempt(u.Email, u.UUID, reasonInvalidCode, client))
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
 	}
-	return s.session(ctx, u.UUID)
+
+	if err = s.redisCache.Delete(ctx, key); err != nil {
+		return TokensResponse{}, fmt.Errorf("two-factor authentication: %w", err)
+	}
+
+	if err = s.throttle.Release(ctx, client.IP, u.Email); err != nil {
+		s.log.Error(err)
+	}
+	if err = s.throttle.Reset(ctx, u.Email); err != nil {
+		s.log.Error(err)
+	}
+
+	return s.session(ctx, id)
 }
 
 func (s *service) RefreshToken(ctx context.Context, dto RefreshTokenDTO) (TokensResponse, error) {
@@ -54,10 +173,48 @@ func (s *service) RefreshToken(ctx context.Context, dto RefreshTokenDTO) (Tokens
 	if id == "" {
 		return TokensResponse{}, fmt.Errorf("refresh token: %s not found", dto.Token)
 	}
+	if err := s.rdb.SRem(ctx, refreshPrefix+id, dto.Token).Err(); err != nil {
+		s.log.Error(err)
+	}
 
 	return s.session(ctx, id)
 }
 
+func (s *service) RevokeSessions(ctx context.Context, uuid string) error {
+	// the access tokens issued before expire within their TTL, the key is not needed after it
+	if err := s.rdb.Set(ctx, revokedPrefix+uuid, time.Now().Unix(), s.duration.Access).Err(); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+
+	tokens, err := s.rdb.SMembers(ctx, refreshPrefix+uuid).Result()
+	if err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	if err = s.rdb.Del(ctx, append(tokens, refreshPrefix+uuid)...).Err(); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+
+	s.log.Infof("sessions of %s were revoked", uuid)
+	return nil
+}
+
+// Revoked is checked for every access token, data is the user UUID.
+func (s *service) Revoked(ctx context.Context, data interface{}, issuedAt time.Time) bool {
+	id, ok := data.(string)
+	if !ok {
+		return true
+	}
+
+	revokedAt, err := s.rdb.Get(ctx, revokedPrefix+id).Int64()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.log.Error(err)
+		}
+		return false
+	}
+	return issuedAt.Unix() < revokedAt
+}
+
 func (s *service) session(ctx context.Context, id string) (TokensResponse, error) {
 	accessToken, err := s.tokenManager.Create(s.duration.Access, id)
 	if err != nil {
@@ -73,8 +230,35 @@ func (s *service) session(ctx context.Context, id string) (TokensResponse, error
 		return TokensResponse{}, fmt.Errorf("refresh token: %w", err)
 	}
 
+	// the refresh tokens of a user are listed so that RevokeSessions can delete them
+	if err = s.rdb.SAdd(ctx, refreshPrefix+id, refreshToken).Err(); err != nil {
+		return TokensResponse{}, fmt.Errorf("refresh token: %w", err)
+	}
+	if err = s.rdb.Expire(ctx, refreshPrefix+id, s.duration.Refresh).Err(); err != nil {
+		return TokensResponse{}, fmt.Errorf("refresh token: %w", err)
+	}
+
 	return TokensResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+func (s *service) fail(ctx context.Context, attempt Attempt) {
+	if err := s.throttle.Fail(ctx, attempt.IP, attempt.Email); err != nil {
+		s.log.Error(err)
+	}
+	s.audit(ctx, attempt)
+}
+
+func (s *service) audit(ctx context.Context, attempt Attempt) {
+	s.log.WithFields(logrus.Fields{
+		"email":  attempt.Email,
+		"ip":     attempt.IP,
+		"reason": attempt.Reason,
+	}).Warn("authentication failed")
+
+	if err := s.attemptStorage.Create(ctx, attempt); err != nil {
+		s.log.Error(err)
+	}
+}
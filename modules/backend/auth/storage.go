@@ -0,0 +1,7 @@
+package auth
+
+import "context"
+
+type AttemptStorage interface {
+	Create(ctx context.Context, model Attempt) error
+}
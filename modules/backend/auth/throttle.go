@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"strings"
+	"time"
+)
+
+const throttlePrefix = "throttle:"
+
+var (
+	// attemptScript returns the milliseconds to wait, or increments the failures of every key. A key over
+	// its limit is locked out, which happens when concurrent attempts pass before the failures are applied.
+	attemptScript = redis.NewScript(`
+local retry = 0
+for i = 1, #KEYS, 3 do
+	for j = 1, 2 do
+		local ttl = redis.call('PTTL', KEYS[i + j])
+		if ttl > retry then retry = ttl end
+	end
+end
+if retry > 0 then return retry end
+
+for i = 1, #KEYS, 3 do
+	local failures = redis.call('INCR', KEYS[i])
+	if failures == 1 then redis.call('PEXPIRE', KEYS[i], ARGV[1]) end
+	local limit = tonumber(ARGV[3 + (i - 1) / 3])
+	if limit > 0 and failures > limit then
+		redis.call('SET', KEYS[i + 2], failures, 'PX', ARGV[2])
+		redis.call('DEL', KEYS[i])
+		retry = tonumber(ARGV[2])
+	end
+end
+return retry
+`)
+
+	releaseScript = redis.NewScript(`
+for i = 1, #KEYS do
+	if redis.call('DECR', KEYS[i]) <= 0 then redis.call('DEL', KEYS[i]) end
+end
+return 0
+`)
+)
+
+type (
+	LockedError struct {
+		RetryAfter time.Duration
+	}
+
+	Throttle interface {
+		Attempt(ctx context.Context, ip, account string) error
+		Release(ctx context.Context, ip, account string) error
+		Fail(ctx context.Context, ip, account string) error
+		Reset(ctx context.Context, account string) error
+		Unlock(ctx context.Context, ip, account string) error
+	}
+
+	throttle struct {
+		cfg config.Throttle
+		rdb redis.UniversalClient
+	}
+)
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func IsLocked(err error) (*LockedError, bool) {
+	var lErr *LockedError
+	if errors.As(err, &lErr) {
+		return lErr, true
+	}
+	return nil, false
+}
+
+func NewThrottle(cfg config.Throttle, rdb redis.UniversalClient) Throttle {
+	return &throttle{
+		cfg: cfg,
+		rdb: rdb,
+	}
+}
+
+// Attempt counts an attempt before it is checked, so concurrent requests can not all pass. It returns
+// a *LockedError while either the client IP or the account is locked out or backing off, or when the
+// attempt goes over the limit.
+func (t *throttle) Attempt(ctx context.Context, ip, account string) error {
+	keys, args := t.scriptArgs(ip, account)
+	if len(keys) == 0 {
+		return nil
+	}
+	retryAfter, err := attemptScript.Run(ctx, t.rdb, keys, args...).Int64()
+	if err != nil {
+		return fmt.Errorf("throttle: %w", err)
+	}
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: time.Duration(retryAfter) * time.Millisecond}
+	}
+	return nil
+}
+
+// Release takes back the attempt of a request which succeeded.
+func (t *throttle) Release(ctx context.Context, ip, account string) error {
+	keys := make([]string, 0, 2)
+	for key := range t.keys(ip, account) {
+		keys = append(keys, failuresKey(key))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := releaseScript.Run(ctx, t.rdb, keys).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("throttle: %w", err)
+	}
+	return nil
+}
+
+// Fail applies the failed attempt counted by Attempt. Every failure doubles the backoff delay and,
+// once the limit is reached, the IP or account is locked out.
+func (t *throttle) Fail(ctx context.Context, ip, account string) error {
+	for key, limit := range t.keys(ip, account) {
+		failures, err := t.rdb.Get(ctx, failuresKey(key)).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("throttle: %w", err)
+		}
+
+		if limit > 0 && failures >= int64(limit) {
+			if err = t.rdb.Set(ctx, lockKey(key), failures, t.cfg.Lockout).Err(); err != nil {
+				return fmt.Errorf("throttle: %w", err)
+			}
+			if err = t.rdb.Del(ctx, failuresKey(key)).Err(); err != nil {
+				return fmt.Errorf("throttle: %w", err)
+			}
+			continue
+		}
+
+		if delay := t.delay(failures); delay > 0 {
+			if err = t.rdb.Set(ctx, waitKey(key), failures, delay).Err(); err != nil {
+				return fmt.Errorf("throttle: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *throttle) Reset(ctx context.Context, account string) error {
+	key := accountKey(account)
+	if err := t.rdb.Del(ctx, failuresKey(key), waitKey(key)).Err(); err != nil {
+		return fmt.Errorf("throttle: %w", err)
+	}
+	return nil
+}
+
+func (t *throttle) Unlock(ctx context.Context, ip, account string) error {
+	keys := make([]string, 0, 6)
+	for key := range t.keys(ip, account) {
+		keys = append(keys, failuresKey(key), waitKey(key), lockKey(key))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := t.rdb.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("throttle: %w", err)
+	}
+	return nil
+}
+
+func (t *throttle) delay(failures int64) time.Duration {
+	if t.cfg.BaseDelay <= 0 || failures <= 0 {
+		return 0
+	}
+	delay := t.cfg.BaseDelay
+	for i := int64(1); i < failures; i++ {
+		delay *= 2
+		if t.cfg.MaxDelay > 0 && delay >= t.cfg.MaxDelay {
+			return t.cfg.MaxDelay
+		}
+	}
+	return delay
+}
+
+// scriptArgs returns the failures, wait and lock keys of the IP and the account for attemptScript,
+// the arguments are the window, the lockout and the limit of each key.
+func (t *throttle) scriptArgs(ip, account string) ([]string, []interface{}) {
+	keys := make([]string, 0, 6)
+	args := []interface{}{t.cfg.Window.Milliseconds(), t.cfg.Lockout.Milliseconds()}
+	for key, limit := range t.keys(ip, account) {
+		keys = append(keys, failuresKey(key), waitKey(key), lockKey(key))
+		args = append(args, limit)
+	}
+	return keys, args
+}
+
+func (t *throttle) keys(ip, account string) map[string]int {
+	keys := make(map[string]int, 2)
+	if ip != "" {
+		keys[ipKey(ip)] = t.cfg.IPMaxAttempts
+	}
+	if account != "" {
+		keys[accountKey(account)] = t.cfg.MaxAttempts
+	}
+	return keys
+}
+
+func ipKey(ip string) string {
+	return throttlePrefix + "ip:" + ip
+}
+
+func accountKey(account string) string {
+	return throttlePrefix + "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+func failuresKey(key string) string {
+	return key + ":failures"
+}
+
+func waitKey(key string) string {
+	return key + ":wait"
+}
+
+func lockKey(key string) string {
+	return key + ":lock"
+}
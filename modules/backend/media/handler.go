@@ -0,0 +1,78 @@
+package media
+
+import (
+	"errors"
+	"github.com/iagapie/go-spring/modules/sys/media"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+const mediaURL = "/api/media"
+
+type Handler struct {
+	Manager        *media.Manager
+	JWTMiddleware  echo.MiddlewareFunc
+	UserMiddleware echo.MiddlewareFunc
+}
+
+func (h *Handler) Register(b *spring.Backend) {
+	b.Match([]string{echo.GET, echo.OPTIONS}, mediaURL, h.list, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-media"
+	b.Match([]string{echo.POST, echo.OPTIONS}, mediaURL, h.upload, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-media-upload"
+	b.Match([]string{echo.DELETE, echo.OPTIONS}, mediaURL, h.delete, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-media-delete"
+}
+
+// list returns the files and directories under ?path=, the root by default.
+func (h *Handler) list(c echo.Context) error {
+	c.Logger().Info("BACKEND MEDIA HANDLER")
+
+	files, err := h.Manager.List(c.Request().Context(), c.QueryParam("path"))
+	if err != nil {
+		return mediaError(err)
+	}
+	return c.JSON(http.StatusOK, files)
+}
+
+// upload stores the multipart field "file" in the directory of the form value "path".
+func (h *Handler) upload(c echo.Context) error {
+	c.Logger().Info("BACKEND MEDIA UPLOAD HANDLER")
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required").SetInternal(err)
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	file, err := h.Manager.Upload(c.Request().Context(), c.FormValue("path"), fh.Filename, f, fh.Size)
+	if err != nil {
+		return mediaError(err)
+	}
+	return c.JSON(http.StatusCreated, file)
+}
+
+// delete removes the file of ?path= with its thumbnails, or an empty directory.
+func (h *Handler) delete(c echo.Context) error {
+	c.Logger().Info("BACKEND MEDIA DELETE HANDLER")
+
+	if err := h.Manager.Delete(c.Request().Context(), c.QueryParam("path")); err != nil {
+		return mediaError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func mediaError(err error) error {
+	switch {
+	case errors.Is(err, media.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, media.ErrTooLarge):
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error()).SetInternal(err)
+	case errors.Is(err, media.ErrInvalidPath), errors.Is(err, media.ErrExtension):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+	}
+	return err
+}
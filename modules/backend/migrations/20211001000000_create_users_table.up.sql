@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    uuid               VARCHAR(36) PRIMARY KEY,
+    name               VARCHAR(100),
+    email              VARCHAR(255),
+    password           TEXT,
+    email_verified_at  TIMESTAMPTZ,
+    two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    two_factor_secret  VARCHAR(64),
+    recovery_codes     TEXT,
+    created_at         TIMESTAMPTZ,
+    updated_at         TIMESTAMPTZ
+);
+
+-- databases created by the former auto migration already have the table
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS recovery_codes TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+CREATE INDEX IF NOT EXISTS idx_users_updated_at ON users (updated_at);
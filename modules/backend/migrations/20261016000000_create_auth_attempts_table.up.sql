@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS auth_attempts
+(
+    id         BIGSERIAL PRIMARY KEY,
+    email      VARCHAR(255),
+    user_uuid  VARCHAR(36),
+    ip         VARCHAR(45),
+    user_agent VARCHAR(255),
+    reason     VARCHAR(64),
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_email ON auth_attempts (email);
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_user_uuid ON auth_attempts (user_uuid);
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_ip ON auth_attempts (ip);
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_created_at ON auth_attempts (created_at);
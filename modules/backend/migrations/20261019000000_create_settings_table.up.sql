@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS settings
+(
+    key        VARCHAR(255) PRIMARY KEY,
+    value      TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS redirects
+(
+    id          BIGSERIAL PRIMARY KEY,
+    source      VARCHAR(2048) NOT NULL,
+    target      VARCHAR(2048) NOT NULL DEFAULT '',
+    type        VARCHAR(16)   NOT NULL DEFAULT 'exact',
+    status      INTEGER       NOT NULL DEFAULT 301,
+    enabled     BOOLEAN       NOT NULL DEFAULT TRUE,
+    hits        BIGINT        NOT NULL DEFAULT 0,
+    last_hit_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_redirects_source ON redirects (source);
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_last_step;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS plugins
+(
+    code       VARCHAR(255) PRIMARY KEY,
+    version    VARCHAR(64),
+    installed  BOOLEAN     NOT NULL DEFAULT FALSE,
+    enabled    BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Dir is where migrate:create puts new core migrations.
+const Dir = "modules/backend/migrations"
+
+//go:embed *.sql
+var files embed.FS
+
+// FS returns the core migrations embedded in the binary.
+func FS() fs.FS {
+	return files
+}
@@ -0,0 +1,109 @@
+package redirect
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{"source", "target", "type", "status", "enabled", "hits"}
+
+// WriteCSV writes the redirects with a header row, the hits are informative and ignored by ReadCSV.
+func WriteCSV(w io.Writer, models []Redirect) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, model := range models {
+		err := cw.Write([]string{
+			model.Source,
+			model.Target,
+			model.Type,
+			strconv.Itoa(model.Status),
+			strconv.FormatBool(model.Enabled),
+			strconv.FormatInt(model.Hits, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads the columns named by the header row, or source, target, type, status and enabled
+// when the first row is not a header. Only the source is required, see NewRedirect for the defaults.
+func ReadCSV(r io.Reader) ([]Redirect, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	columns := map[string]int{"source": 0, "target": 1, "type": 2, "status": 3, "enabled": 4}
+	models := make([]Redirect, 0)
+	sources := make(map[string]int)
+
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRedirect, err)
+		}
+
+		if line == 1 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "source") {
+			columns = make(map[string]int)
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		if len(field("source")) == 0 {
+			continue
+		}
+
+		dto := RedirectDTO{
+			Source: field("source"),
+			Target: field("target"),
+			Type:   strings.ToLower(field("type")),
+		}
+		if s := field("status"); len(s) > 0 {
+			if dto.Status, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("%w: line %d: status %q is not a number", ErrInvalidRedirect, line, s)
+			}
+		}
+		if s := field("enabled"); len(s) > 0 {
+			enabled, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: enabled %q is not a boolean", ErrInvalidRedirect, line, s)
+			}
+			dto.Enabled = &enabled
+		}
+
+		model := NewRedirect(dto)
+		if err = Validate(model); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		// the last row of a source wins, one upsert can not touch a row twice
+		if i, ok := sources[model.Source]; ok {
+			models[i] = model
+			continue
+		}
+		sources[model.Source] = len(models)
+		models = append(models, model)
+	}
+
+	return models, nil
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/backend/redirect"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+var _ redirect.Storage = &storage{}
+
+type storage struct {
+	db  *postgresdb.Database
+	log *logrus.Entry
+}
+
+func NewStorage(postgres *postgresdb.Database, log *logrus.Entry) redirect.Storage {
+	return &storage{
+		db:  postgres,
+		log: log,
+	}
+}
+
+func (s *storage) All(ctx context.Context) ([]redirect.Redirect, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var models []redirect.Redirect
+	if err := s.db.WithContext(ctx).Order("id").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	return models, nil
+}
+
+func (s *storage) FindByID(ctx context.Context, id uint) (redirect.Redirect, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var model redirect.Redirect
+	if err := s.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model, redirect.ErrRecordNotFound
+		}
+		return model, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	return model, nil
+}
+
+func (s *storage) Create(ctx context.Context, model redirect.Redirect) (uint, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Create(&model).Error; err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return 0, redirect.ErrRecordConflict
+		}
+		return 0, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+
+	s.log.Tracef("Created redirect: %d.\n", model.ID)
+
+	return model.ID, nil
+}
+
+func (s *storage) Update(ctx context.Context, model redirect.Redirect) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	model.UpdatedAt = time.Now()
+
+	result := s.db.WithContext(ctx).Model(&model).
+		Select("source", "target", "type", "status", "enabled", "updated_at").
+		Updates(&model)
+	if err := result.Error; err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return redirect.ErrRecordConflict
+		}
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return redirect.ErrRecordNotFound
+	}
+
+	s.log.Tracef("Updated redirect: %d.\n", model.ID)
+
+	return nil
+}
+
+func (s *storage) Delete(ctx context.Context, id uint) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&redirect.Redirect{}, "id = ?", id)
+	if err := result.Error; err != nil {
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return redirect.ErrRecordNotFound
+	}
+
+	s.log.Tracef("Deleted redirect: %d.\n", id)
+
+	return nil
+}
+
+func (s *storage) Import(ctx context.Context, models []redirect.Redirect) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target", "type", "status", "enabled", "updated_at"}),
+		}).CreateInBatches(&models, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+
+	s.log.Tracef("Imported %d redirects.\n", len(models))
+
+	return nil
+}
+
+func (s *storage) Hit(ctx context.Context, hits map[uint]int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, n := range hits {
+			err := tx.Model(&redirect.Redirect{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+				"hits":        gorm.Expr("hits + ?", n),
+				"last_hit_at": at,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	return nil
+}
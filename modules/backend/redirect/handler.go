@@ -0,0 +1,204 @@
+package redirect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	redirectsURL      = "/api/redirects"
+	redirectURL       = "/api/redirects/:id"
+	exportRedirectURL = "/api/redirects/export"
+	importRedirectURL = "/api/redirects/import"
+
+	maxImportSize = 10 << 20
+)
+
+type Handler struct {
+	Manager        *Manager
+	JWTMiddleware  echo.MiddlewareFunc
+	UserMiddleware echo.MiddlewareFunc
+}
+
+func (h *Handler) Register(b *spring.Backend) {
+	mg := []string{echo.GET, echo.OPTIONS}
+	mp := []string{echo.POST, echo.OPTIONS}
+	b.Match(mg, redirectsURL, h.list, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects"
+	b.Match(mp, redirectsURL, h.create, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects-create"
+	b.Match(mg, exportRedirectURL, h.export, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects-export"
+	b.Match(mp, importRedirectURL, h.importCSV, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects-import"
+	b.Match(mg, redirectURL, h.get, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects-get"
+	b.Match([]string{echo.PUT, echo.OPTIONS}, redirectURL, h.update, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects-update"
+	b.Match([]string{echo.DELETE, echo.OPTIONS}, redirectURL, h.delete, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-redirects-delete"
+}
+
+func (h *Handler) list(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS HANDLER")
+
+	redirects, err := h.Manager.All(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ListResponse{Redirects: redirects})
+}
+
+func (h *Handler) get(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS GET HANDLER")
+
+	id, err := paramID(c)
+	if err != nil {
+		return err
+	}
+
+	redirect, err := h.Manager.Get(c.Request().Context(), id)
+	if err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.JSON(http.StatusOK, redirect)
+}
+
+func (h *Handler) create(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS CREATE HANDLER")
+
+	dto, err := bindDTO(c)
+	if err != nil {
+		return err
+	}
+
+	redirect, err := h.Manager.Create(c.Request().Context(), dto)
+	if err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.JSON(http.StatusCreated, redirect)
+}
+
+func (h *Handler) update(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS UPDATE HANDLER")
+
+	id, err := paramID(c)
+	if err != nil {
+		return err
+	}
+
+	dto, err := bindDTO(c)
+	if err != nil {
+		return err
+	}
+
+	redirect, err := h.Manager.Update(c.Request().Context(), id, dto)
+	if err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.JSON(http.StatusOK, redirect)
+}
+
+func (h *Handler) delete(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS DELETE HANDLER")
+
+	id, err := paramID(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.Manager.Delete(c.Request().Context(), id); err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// export sends every redirect as CSV, see WriteCSV for the columns.
+func (h *Handler) export(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS EXPORT HANDLER")
+
+	redirects, err := h.Manager.All(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = WriteCSV(&buf, redirects); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("redirects-%s.csv", time.Now().Format("20060102-150405"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// importCSV reads the multipart field "file", redirects with a source which already exists are updated.
+// Nothing is imported when a row is invalid.
+func (h *Handler) importCSV(c echo.Context) error {
+	c.Logger().Info("BACKEND REDIRECTS IMPORT HANDLER")
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required").SetInternal(err)
+	}
+	if fh.Size > maxImportSize {
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	redirects, err := ReadCSV(f)
+	if err != nil {
+		return toHTTPError(err)
+	}
+
+	if err = h.Manager.Import(c.Request().Context(), redirects); err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.JSON(http.StatusCreated, ImportResponse{Imported: len(redirects)})
+}
+
+func bindDTO(c echo.Context) (RedirectDTO, error) {
+	var dto RedirectDTO
+
+	c.Logger().Debug("bind RedirectDTO")
+	if err := c.Bind(&dto); err != nil {
+		return dto, err
+	}
+
+	c.Logger().Debug("validate RedirectDTO")
+	if err := c.Validate(&dto); err != nil {
+		return dto, err
+	}
+
+	return dto, nil
+}
+
+func paramID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, echo.ErrNotFound.SetInternal(err)
+	}
+	return uint(id), nil
+}
+
+func toHTTPError(err error) error {
+	switch {
+	case errors.Is(err, ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrRecordConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrInvalidRedirect):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+	}
+	return err
+}
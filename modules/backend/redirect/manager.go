@@ -0,0 +1,319 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/router"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidRedirect = errors.New("redirect is invalid")
+
+	targetParamRe = regexp.MustCompile(`:([a-zA-Z_][a-zA-Z0-9_]*)`)
+)
+
+type (
+	Manager struct {
+		storage Storage
+		log     *logrus.Entry
+		mu      sync.RWMutex
+		rules   *rules
+		hits    map[uint]int64
+	}
+
+	// rules are the enabled redirects compiled for matching, exact sources are checked first,
+	// then the patterns and then the regular expressions in the order they were created.
+	rules struct {
+		exact    map[string]Redirect
+		patterns router.Router
+		byID     map[string]Redirect
+		regexes  []regexRule
+	}
+
+	regexRule struct {
+		re       *regexp.Regexp
+		redirect Redirect
+	}
+)
+
+func NewManager(storage Storage, log *logrus.Entry) *Manager {
+	return &Manager{
+		storage: storage,
+		log:     log,
+		rules:   compile(nil, log),
+		hits:    make(map[uint]int64),
+	}
+}
+
+func (m *Manager) All(ctx context.Context) ([]Redirect, error) {
+	return m.storage.All(ctx)
+}
+
+func (m *Manager) Get(ctx context.Context, id uint) (Redirect, error) {
+	return m.storage.FindByID(ctx, id)
+}
+
+func (m *Manager) Create(ctx context.Context, dto RedirectDTO) (Redirect, error) {
+	model := NewRedirect(dto)
+	if err := Validate(model); err != nil {
+		return Redirect{}, err
+	}
+
+	id, err := m.storage.Create(ctx, model)
+	if err != nil {
+		return Redirect{}, err
+	}
+	model.ID = id
+	return model, m.Load(ctx)
+}
+
+func (m *Manager) Update(ctx context.Context, id uint, dto RedirectDTO) (Redirect, error) {
+	model, err := m.storage.FindByID(ctx, id)
+	if err != nil {
+		return Redirect{}, err
+	}
+
+	model.apply(dto)
+	if err = Validate(model); err != nil {
+		return Redirect{}, err
+	}
+	if err = m.storage.Update(ctx, model); err != nil {
+		return Redirect{}, err
+	}
+	return model, m.Load(ctx)
+}
+
+func (m *Manager) Delete(ctx context.Context, id uint) error {
+	if err := m.storage.Delete(ctx, id); err != nil {
+		return err
+	}
+	return m.Load(ctx)
+}
+
+func (m *Manager) Import(ctx context.Context, models []Redirect) error {
+	if err := m.storage.Import(ctx, models); err != nil {
+		return err
+	}
+	return m.Load(ctx)
+}
+
+// Load reads the redirects from the storage and replaces the compiled rules.
+func (m *Manager) Load(ctx context.Context) error {
+	models, err := m.storage.All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load redirects. error: %w", err)
+	}
+
+	r := compile(models, m.log)
+	m.mu.Lock()
+	m.rules = r
+	m.mu.Unlock()
+	return nil
+}
+
+// Watch loads the redirects changed by other instances and saves the hit counters every interval.
+// It returns once ctx is done and the remaining hits are saved.
+func (m *Manager) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.flush(context.Background())
+			return
+		case <-ticker.C:
+			m.flush(ctx)
+			if err := m.Load(ctx); err != nil {
+				m.log.Error(err)
+			}
+		}
+	}
+}
+
+// Middleware answers the requests matching a redirect, 410 is passed to the HTTP error handler
+// so the theme can render its error page.
+func (m *Manager) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		redirect, target, ok := m.Match(req.URL.Path, req.URL.RawQuery)
+		if !ok {
+			return next(c)
+		}
+
+		m.hit(redirect.ID)
+
+		if redirect.Status == http.StatusGone {
+			return echo.NewHTTPError(http.StatusGone)
+		}
+		return c.Redirect(redirect.Status, target)
+	}
+}
+
+// Match returns the redirect of the path and its target. The query is kept unless the target has one,
+// or the source of an exact redirect has one.
+func (m *Manager) Match(path, query string) (Redirect, string, bool) {
+	m.mu.RLock()
+	r := m.rules
+	m.mu.RUnlock()
+
+	path = router.NormalizeUrl(path)
+
+	if len(query) > 0 {
+		if redirect, ok := r.exact[exactKey(path+"?"+query)]; ok {
+			return redirect, redirect.Target, true
+		}
+	}
+	if redirect, ok := r.exact[exactKey(path)]; ok {
+		return redirect, withQuery(redirect.Target, query), true
+	}
+
+	if name, params, ok := r.patterns.Find(path); ok {
+		redirect := r.byID[name]
+		target := targetParamRe.ReplaceAllStringFunc(redirect.Target, func(s string) string {
+			if value, ok := params[s[1:]]; ok {
+				return value
+			}
+			return s
+		})
+		return redirect, withQuery(sameOrigin(redirect.Target, target), query), true
+	}
+
+	for _, rule := range r.regexes {
+		if match := rule.re.FindStringSubmatchIndex(path); match != nil {
+			target := string(rule.re.ExpandString(nil, rule.redirect.Target, path, match))
+			return rule.redirect, withQuery(sameOrigin(rule.redirect.Target, target), query), true
+		}
+	}
+
+	return Redirect{}, "", false
+}
+
+// Validate checks what the DTO validation can not: the source of the type and a target for the redirect status codes.
+func Validate(model Redirect) error {
+	switch model.Type {
+	case TypeExact, TypePattern:
+		if !strings.HasPrefix(model.Source, "/") {
+			return fmt.Errorf("%w: source %s has to start with /", ErrInvalidRedirect, model.Source)
+		}
+	case TypeRegex:
+		if _, err := regexp.Compile(model.Source); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRedirect, err)
+		}
+	default:
+		return fmt.Errorf("%w: type must be %s, %s or %s, got %q", ErrInvalidRedirect, TypeExact, TypePattern, TypeRegex, model.Type)
+	}
+
+	switch model.Status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect:
+		if len(model.Target) == 0 {
+			return fmt.Errorf("%w: target is required for %d", ErrInvalidRedirect, model.Status)
+		}
+		if model.Type == TypeExact && router.NormalizeUrl(model.Source) == router.NormalizeUrl(model.Target) {
+			return fmt.Errorf("%w: %s redirects to itself", ErrInvalidRedirect, model.Source)
+		}
+	case http.StatusGone:
+	default:
+		return fmt.Errorf("%w: status must be 301, 302, 307 or 410, got %d", ErrInvalidRedirect, model.Status)
+	}
+	return nil
+}
+
+func (m *Manager) hit(id uint) {
+	m.mu.Lock()
+	m.hits[id]++
+	m.mu.Unlock()
+}
+
+func (m *Manager) flush(ctx context.Context) {
+	m.mu.Lock()
+	hits := m.hits
+	m.hits = make(map[uint]int64)
+	m.mu.Unlock()
+
+	if len(hits) == 0 {
+		return
+	}
+	if err := m.storage.Hit(ctx, hits, time.Now()); err != nil {
+		m.log.Error(err)
+	}
+}
+
+func compile(models []Redirect, log *logrus.Entry) *rules {
+	r := &rules{
+		exact:    make(map[string]Redirect),
+		patterns: router.New(),
+		byID:     make(map[string]Redirect),
+	}
+
+	for _, model := range models {
+		if !model.Enabled {
+			continue
+		}
+
+		switch model.Type {
+		case TypeExact:
+			r.exact[exactKey(model.Source)] = model
+		case TypePattern:
+			name := strconv.FormatUint(uint64(model.ID), 10)
+			r.patterns.Route(name, model.Source)
+			r.byID[name] = model
+		case TypeRegex:
+			re, err := regexp.Compile(model.Source)
+			if err != nil {
+				log.Warnf("redirect %d: %v", model.ID, err)
+				continue
+			}
+			r.regexes = append(r.regexes, regexRule{re: re, redirect: model})
+		}
+	}
+	r.patterns.Sort()
+
+	return r
+}
+
+// exactKey compares the paths case-insensitively, like the static segments of the page URLs.
+func exactKey(source string) string {
+	path, query := source, ""
+	if i := strings.IndexByte(source, '?'); i != -1 {
+		path, query = source[:i], source[i:]
+	}
+	return strings.ToLower(router.NormalizeUrl(path)) + query
+}
+
+// sameOrigin keeps a target expanded from a relative template on this site, the request /old//evil.com
+// must not become the protocol-relative URL //evil.com. Browsers read \ as / and skip tabs and newlines,
+// so a leading run of them is collapsed into one slash.
+func sameOrigin(template, target string) string {
+	if u, err := url.Parse(template); err == nil && (u.Scheme != "" || u.Host != "") {
+		return target
+	}
+
+	path := strings.TrimLeftFunc(target, func(r rune) bool {
+		return r == '/' || r == '\\' || r <= ' '
+	})
+	if len(path) < len(target) {
+		return "/" + path
+	}
+	if u, err := url.Parse(target); err != nil || u.Scheme != "" {
+		return "/" + target
+	}
+	return target
+}
+
+func withQuery(target, query string) string {
+	if len(query) == 0 || strings.Contains(target, "?") {
+		return target
+	}
+	return target + "?" + query
+}
@@ -0,0 +1,57 @@
+package redirect
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hitStorage records the saved hits, it has no redirects.
+type hitStorage struct {
+	Storage
+	mu   sync.Mutex
+	hits map[uint]int64
+}
+
+func (s *hitStorage) All(context.Context) ([]Redirect, error) {
+	return nil, nil
+}
+
+func (s *hitStorage) Hit(_ context.Context, hits map[uint]int64, _ time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, n := range hits {
+		s.hits[id] += n
+	}
+	return nil
+}
+
+func TestWatchSavesHitsWhenDone(t *testing.T) {
+	storage := &hitStorage{hits: make(map[uint]int64)}
+	m := NewManager(storage, logrus.NewEntry(logrus.New()))
+	m.hit(1)
+	m.hit(1)
+	m.hit(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Watch(ctx, time.Hour)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after ctx was done")
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	if storage.hits[1] != 2 || storage.hits[2] != 1 {
+		t.Errorf("saved hits = %v, want map[1:2 2:1]", storage.hits)
+	}
+}
@@ -0,0 +1,64 @@
+package redirect
+
+import (
+	"time"
+)
+
+const (
+	TypeExact   = "exact"
+	TypePattern = "pattern"
+	TypeRegex   = "regex"
+)
+
+type RedirectDTO struct {
+	Source  string `json:"source,omitempty" validate:"required,max=2048"`
+	Target  string `json:"target,omitempty" validate:"required_unless=Status 410,max=2048"`
+	Type    string `json:"type,omitempty" validate:"omitempty,oneof=exact pattern regex"`
+	Status  int    `json:"status,omitempty" validate:"omitempty,oneof=301 302 307 410"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+type Redirect struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Source    string     `json:"source" gorm:"uniqueIndex;size:2048"`
+	Target    string     `json:"target" gorm:"size:2048"`
+	Type      string     `json:"type" gorm:"size:16"`
+	Status    int        `json:"status"`
+	Enabled   bool       `json:"enabled"`
+	Hits      int64      `json:"hits"`
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at,omitempty"`
+}
+
+type ListResponse struct {
+	Redirects []Redirect `json:"redirects"`
+}
+
+type ImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// NewRedirect fills the defaults, an exact 301 which is enabled.
+func NewRedirect(dto RedirectDTO) Redirect {
+	model := Redirect{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	model.apply(dto)
+	return model
+}
+
+func (r *Redirect) apply(dto RedirectDTO) {
+	r.Source = dto.Source
+	r.Target = dto.Target
+	r.Type = dto.Type
+	if r.Type == "" {
+		r.Type = TypeExact
+	}
+	r.Status = dto.Status
+	if r.Status == 0 {
+		r.Status = 301
+	}
+	r.Enabled = dto.Enabled == nil || *dto.Enabled
+}
@@ -0,0 +1,24 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRecordNotFound = errors.New("redirect not found")
+	ErrRecordConflict = errors.New("redirect with this source already exists")
+)
+
+type Storage interface {
+	All(ctx context.Context) ([]Redirect, error)
+	FindByID(ctx context.Context, id uint) (Redirect, error)
+	Create(ctx context.Context, model Redirect) (uint, error)
+	Update(ctx context.Context, model Redirect) error
+	Delete(ctx context.Context, id uint) error
+	// Import creates the redirects, or updates the ones with the same source, in one transaction.
+	Import(ctx context.Context, models []Redirect) error
+	// Hit adds to the hit counters, hits maps the redirect ID to its new hits.
+	Hit(ctx context.Context, hits map[uint]int64, at time.Time) error
+}
@@ -0,0 +1,32 @@
+package schedule
+
+import (
+	"github.com/iagapie/go-spring/modules/sys/schedule"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+const tasksURL = "/api/schedule"
+
+type Handler struct {
+	Scheduler      *schedule.Scheduler
+	JWTMiddleware  echo.MiddlewareFunc
+	UserMiddleware echo.MiddlewareFunc
+}
+
+func (h *Handler) Register(b *spring.Backend) {
+	mg := []string{echo.GET, echo.OPTIONS}
+	b.Match(mg, tasksURL, h.tasks, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-schedule"
+}
+
+func (h *Handler) tasks(c echo.Context) error {
+	c.Logger().Info("BACKEND SCHEDULE HANDLER")
+
+	items, err := h.Scheduler.Status(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,167 @@
+package theme
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"sort"
+)
+
+const (
+	themesURL      = "/api/themes"
+	activeThemeURL = "/api/themes/active"
+	exportThemeURL = "/api/themes/:name/export"
+	importThemeURL = "/api/themes/import"
+)
+
+type (
+	Handler struct {
+		Storage        theme.Storage
+		ThemesPath     string
+		Contents       theme.ContentMap
+		JWTMiddleware  echo.MiddlewareFunc
+		UserMiddleware echo.MiddlewareFunc
+	}
+
+	UseThemeDTO struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	ThemeResponse struct {
+		theme.Cfg
+		Dir    string   `json:"dir"`
+		Active bool     `json:"active"`
+		Chain  []string `json:"chain"`
+		Error  string   `json:"error,omitempty"`
+	}
+)
+
+func (h *Handler) Register(b *spring.Backend) {
+	mg := []string{echo.GET, echo.OPTIONS}
+	mp := []string{echo.PUT, echo.OPTIONS}
+	b.Match(mg, themesURL, h.themes, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-themes"
+	b.Match(mp, activeThemeURL, h.use, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-themes-use"
+	b.Match(mg, exportThemeURL, h.export, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-themes-export"
+	b.Match([]string{echo.POST, echo.OPTIONS}, importThemeURL, h.importTheme, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-themes-import"
+}
+
+func (h *Handler) themes(c echo.Context) error {
+	c.Logger().Info("BACKEND THEMES HANDLER")
+
+	items := make([]ThemeResponse, 0)
+	for name, t := range theme.Themes() {
+		item := ThemeResponse{Dir: name, Active: t.IsActive()}
+		if cfg, err := t.Cfg(); err != nil {
+			item.Error = err.Error()
+		} else {
+			item.Cfg = cfg
+		}
+		chain, err := t.Chain()
+		if err != nil {
+			item.Error = err.Error()
+		}
+		for _, c := range chain {
+			item.Chain = append(item.Chain, c.Dir())
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Dir < items[j].Dir
+	})
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) use(c echo.Context) error {
+	c.Logger().Info("BACKEND THEMES USE HANDLER")
+
+	var dto UseThemeDTO
+
+	c.Logger().Debug("bind UseThemeDTO")
+	if err := c.Bind(&dto); err != nil {
+		return err
+	}
+
+	c.Logger().Debug("validate UseThemeDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	if err := theme.Use(c.Request().Context(), h.Storage, dto.Name); err != nil {
+		if errors.Is(err, theme.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// export sends the theme as a zip archive, ?content=true adds the database content of plugins.
+func (h *Handler) export(c echo.Context) error {
+	c.Logger().Info("BACKEND THEMES EXPORT HANDLER")
+
+	name := c.Param("name")
+	t, ok := theme.Get(name)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "theme not found")
+	}
+
+	var contents theme.ContentMap
+	if c.QueryParam("content") == "true" {
+		contents = h.Contents
+	}
+
+	var buf bytes.Buffer
+	if err := theme.Export(c.Request().Context(), &buf, t, contents); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name+".zip"))
+	return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// importTheme reads the archive from the multipart field "file", the form values name, force
+// and content match the options of theme:import.
+func (h *Handler) importTheme(c echo.Context) error {
+	c.Logger().Info("BACKEND THEMES IMPORT HANDLER")
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required").SetInternal(err)
+	}
+	if fh.Size > theme.MaxImportSize {
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts := theme.ImportOptions{
+		Name:  c.FormValue("name"),
+		Force: c.FormValue("force") == "true",
+	}
+	if c.FormValue("content") == "true" {
+		opts.Content = h.Contents
+	}
+
+	name, err := theme.Import(c.Request().Context(), f, fh.Size, h.ThemesPath, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, theme.ErrArchive), errors.Is(err, theme.ErrNotFound):
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+		case errors.Is(err, theme.ErrExists):
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{"name": name})
+}
@@ -50,6 +50,41 @@ func (s *storage) Create(ctx context.Context, model user.User) error {
 	return nil
 }
 
+func (s *storage) Update(ctx context.Context, model user.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	model.UpdatedAt = time.Now()
+
+	result := s.db.WithContext(ctx).Model(&model).Select("*").Omit("created_at", "two_factor_last_step").Updates(&model)
+	if err := result.Error; err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return user.ErrRecordConflict
+		}
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrRecordNotFound
+	}
+
+	s.log.Tracef("Updated user: %s.\n", model.UUID)
+
+	return nil
+}
+
+func (s *storage) UseTwoFactorStep(ctx context.Context, uuid string, step int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Model(&user.User{}).
+		Where("uuid = ? AND two_factor_last_step < ?", uuid, step).
+		UpdateColumn("two_factor_last_step", step)
+	if err := result.Error; err != nil {
+		return false, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (s *storage) findOne(ctx context.Context, column, value string) (user.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -7,7 +7,10 @@ import (
 )
 
 const (
-	meURL = "/api/me"
+	meURL               = "/api/me"
+	twoFactorSetupURL   = "/api/me/2fa/setup"
+	twoFactorConfirmURL = "/api/me/2fa/confirm"
+	twoFactorDisableURL = "/api/me/2fa/disable"
 )
 
 type Handler struct {
@@ -19,9 +22,83 @@ type Handler struct {
 
 func (h *Handler) Register(b *spring.Backend) {
 	mg := []string{echo.GET, echo.OPTIONS}
+	mp := []string{echo.POST, echo.OPTIONS}
 	b.Match(mg, meURL, h.me, h.JWTMiddleware, h.UserMiddleware)
+	b.Match(mp, twoFactorSetupURL, h.twoFactorSetup, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-2fa-setup"
+	b.Match(mp, twoFactorConfirmURL, h.twoFactorConfirm, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-2fa-confirm"
+	b.Match(mp, twoFactorDisableURL, h.twoFactorDisable, h.JWTMiddleware, h.UserMiddleware)[0].Name = "backend-2fa-disable"
 }
 
 func (h *Handler) me(c echo.Context) error {
 	return c.JSON(http.StatusOK, c.Get(h.UserContextKey))
 }
+
+func (h *Handler) twoFactorSetup(c echo.Context) error {
+	c.Logger().Info("BACKEND 2FA SETUP HANDLER")
+
+	r, err := h.Service.SetupTwoFactor(c.Request().Context(), h.currentUser(c).UUID)
+	if err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.JSON(http.StatusOK, &r)
+}
+
+func (h *Handler) twoFactorConfirm(c echo.Context) error {
+	c.Logger().Info("BACKEND 2FA CONFIRM HANDLER")
+
+	var dto TwoFactorCodeDTO
+
+	c.Logger().Debug("bind TwoFactorCodeDTO")
+	if err := c.Bind(&dto); err != nil {
+		return err
+	}
+
+	c.Logger().Debug("validate TwoFactorCodeDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	r, err := h.Service.ConfirmTwoFactor(c.Request().Context(), h.currentUser(c).UUID, dto)
+	if err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.JSON(http.StatusOK, &r)
+}
+
+func (h *Handler) twoFactorDisable(c echo.Context) error {
+	c.Logger().Info("BACKEND 2FA DISABLE HANDLER")
+
+	var dto TwoFactorCodeDTO
+
+	c.Logger().Debug("bind TwoFactorCodeDTO")
+	if err := c.Bind(&dto); err != nil {
+		return err
+	}
+
+	c.Logger().Debug("validate TwoFactorCodeDTO")
+	if err := c.Validate(&dto); err != nil {
+		return err
+	}
+
+	if err := h.Service.DisableTwoFactor(c.Request().Context(), h.currentUser(c).UUID, dto); err != nil {
+		return toHTTPError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) currentUser(c echo.Context) User {
+	return c.Get(h.UserContextKey).(User)
+}
+
+func toHTTPError(err error) error {
+	switch err {
+	case ErrTwoFactorInvalidCode:
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+	case ErrTwoFactorEnabled, ErrTwoFactorNotEnabled, ErrTwoFactorNotStarted:
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
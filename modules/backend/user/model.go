@@ -12,19 +12,37 @@ type CreateUserDTO struct {
 	RepeatPassword string `json:"repeat_password,omitempty" validate:"eqfield=Password"`
 }
 
+type TwoFactorCodeDTO struct {
+	Code string `json:"code,omitempty" validate:"required,len=6,numeric"`
+}
+
 type User struct {
-	UUID      string    `json:"uuid,omitempty" gorm:"primaryKey;size:36"`
-	Name      string    `json:"name,omitempty" gorm:"size:100"`
-	Email     string    `json:"email,omitempty" gorm:"uniqueIndex;size:255"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"created_at,omitempty" gorm:"index"`
-	UpdatedAt time.Time `json:"updated_at,omitempty" gorm:"index"`
+	UUID              string     `json:"uuid,omitempty" gorm:"primaryKey;size:36"`
+	Name              string     `json:"name,omitempty" gorm:"size:100"`
+	Email             string     `json:"email,omitempty" gorm:"uniqueIndex;size:255"`
+	Password          string     `json:"-"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled  bool       `json:"two_factor_enabled"`
+	TwoFactorSecret   string     `json:"-" gorm:"size:64"`
+	TwoFactorLastStep int64      `json:"-"`
+	RecoveryCodes     string     `json:"-" gorm:"type:text"`
+	CreatedAt         time.Time  `json:"created_at,omitempty" gorm:"index"`
+	UpdatedAt         time.Time  `json:"updated_at,omitempty" gorm:"index"`
 }
 
 type ListResponse struct {
 	Users []User `json:"users,omitempty"`
 }
 
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret,omitempty"`
+	URI    string `json:"uri,omitempty"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
 func NewUser(dto CreateUserDTO) User {
 	return User{
 		UUID:      uuid.NewString(),
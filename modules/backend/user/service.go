@@ -2,28 +2,58 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"github.com/iagapie/go-spring/modules/sys/password"
+	"github.com/iagapie/go-spring/modules/sys/totp"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeSep      = "\n"
+)
+
+var (
+	recoveryCodeRe = regexp.MustCompile(`^[` + recoveryCodeAlphabet + `]{5}-[` + recoveryCodeAlphabet + `]{5}$`)
+
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotStarted  = errors.New("two-factor authentication enrolment was not started")
+	ErrTwoFactorInvalidCode = errors.New("two-factor authentication code is invalid")
 )
 
 type (
 	Service interface {
 		GetByEmailAndPassword(ctx context.Context, email, password string) (User, error)
+		GetByEmail(ctx context.Context, email string) (User, error)
 		GetByUUID(ctx context.Context, uuid string) (User, error)
 		Create(ctx context.Context, dto CreateUserDTO) (string, error)
+		SetPassword(ctx context.Context, uuid, password string) error
+		VerifyEmail(ctx context.Context, uuid string) error
+		SetupTwoFactor(ctx context.Context, uuid string) (TwoFactorSetupResponse, error)
+		ConfirmTwoFactor(ctx context.Context, uuid string, dto TwoFactorCodeDTO) (RecoveryCodesResponse, error)
+		DisableTwoFactor(ctx context.Context, uuid string, dto TwoFactorCodeDTO) error
+		ResetTwoFactor(ctx context.Context, uuid string) error
+		VerifyTwoFactor(ctx context.Context, uuid, code string) error
 	}
 
 	service struct {
 		storage Storage
 		encoder password.Encoder
+		issuer  string
 	}
 )
 
-func NewService(storage Storage, encoder password.Encoder) Service {
+func NewService(storage Storage, encoder password.Encoder, issuer string) Service {
 	return &service{
 		storage: storage,
 		encoder: encoder,
+		issuer:  issuer,
 	}
 }
 
@@ -35,6 +65,10 @@ func (s *service) GetByEmailAndPassword(ctx context.Context, email, password str
 	return User{}, ErrRecordNotFound
 }
 
+func (s *service) GetByEmail(ctx context.Context, email string) (User, error) {
+	return s.storage.FindByEmail(ctx, email)
+}
+
 func (s *service) GetByUUID(ctx context.Context, uuid string) (User, error) {
 	return s.storage.FindByUUID(ctx, uuid)
 }
@@ -54,3 +88,213 @@ func (s *service) Create(ctx context.Context, dto CreateUserDTO) (string, error)
 	}
 	return model.UUID, nil
 }
+
+func (s *service) SetPassword(ctx context.Context, uuid, password string) error {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := s.encoder.Encode(password)
+	if err != nil {
+		return fmt.Errorf("failed to set password. error: %w", err)
+	}
+
+	model.Password = encoded
+	if err = s.storage.Update(ctx, model); err != nil {
+		return fmt.Errorf("failed to set password. error: %w", err)
+	}
+	return nil
+}
+
+func (s *service) VerifyEmail(ctx context.Context, uuid string) error {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if model.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	model.EmailVerifiedAt = &now
+	if err = s.storage.Update(ctx, model); err != nil {
+		return fmt.Errorf("failed to verify email. error: %w", err)
+	}
+	return nil
+}
+
+// SetupTwoFactor stores a new pending secret. It is not enforced on sign-in until ConfirmTwoFactor succeeds.
+func (s *service) SetupTwoFactor(ctx context.Context, uuid string) (TwoFactorSetupResponse, error) {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return TwoFactorSetupResponse{}, err
+	}
+	if model.TwoFactorEnabled {
+		return TwoFactorSetupResponse{}, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TwoFactorSetupResponse{}, fmt.Errorf("failed to setup two-factor. error: %w", err)
+	}
+
+	model.TwoFactorSecret = secret
+	model.RecoveryCodes = ""
+	if err = s.storage.Update(ctx, model); err != nil {
+		return TwoFactorSetupResponse{}, fmt.Errorf("failed to setup two-factor. error: %w", err)
+	}
+
+	return TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    totp.URI(s.issuer, model.Email, secret),
+	}, nil
+}
+
+func (s *service) ConfirmTwoFactor(ctx context.Context, uuid string, dto TwoFactorCodeDTO) (RecoveryCodesResponse, error) {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return RecoveryCodesResponse{}, err
+	}
+	if model.TwoFactorEnabled {
+		return RecoveryCodesResponse{}, ErrTwoFactorEnabled
+	}
+	if model.TwoFactorSecret == "" {
+		return RecoveryCodesResponse{}, ErrTwoFactorNotStarted
+	}
+	step, ok := totp.ValidateStep(model.TwoFactorSecret, dto.Code, time.Now(), model.TwoFactorLastStep)
+	if !ok {
+		return RecoveryCodesResponse{}, ErrTwoFactorInvalidCode
+	}
+
+	codes, hashes, err := s.recoveryCodes()
+	if err != nil {
+		return RecoveryCodesResponse{}, fmt.Errorf("failed to confirm two-factor. error: %w", err)
+	}
+
+	model.TwoFactorEnabled = true
+	model.RecoveryCodes = strings.Join(hashes, recoveryCodeSep)
+	if err = s.storage.Update(ctx, model); err != nil {
+		return RecoveryCodesResponse{}, fmt.Errorf("failed to confirm two-factor. error: %w", err)
+	}
+	if _, err = s.storage.UseTwoFactorStep(ctx, uuid, step); err != nil {
+		return RecoveryCodesResponse{}, fmt.Errorf("failed to confirm two-factor. error: %w", err)
+	}
+
+	return RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *service) DisableTwoFactor(ctx context.Context, uuid string, dto TwoFactorCodeDTO) error {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if !model.TwoFactorEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if err = s.useCode(ctx, model, dto.Code); err != nil {
+		return err
+	}
+	return s.ResetTwoFactor(ctx, uuid)
+}
+
+func (s *service) ResetTwoFactor(ctx context.Context, uuid string) error {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	model.TwoFactorEnabled = false
+	model.TwoFactorSecret = ""
+	model.RecoveryCodes = ""
+	if err = s.storage.Update(ctx, model); err != nil {
+		return fmt.Errorf("failed to reset two-factor. error: %w", err)
+	}
+	return nil
+}
+
+// VerifyTwoFactor accepts either a TOTP code or one of the unused recovery codes.
+// A TOTP code is accepted once, a recovery code is removed once it has been used.
+func (s *service) VerifyTwoFactor(ctx context.Context, uuid, code string) error {
+	model, err := s.storage.FindByUUID(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if !model.TwoFactorEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if len(strings.TrimSpace(code)) == totp.Digits {
+		return s.useCode(ctx, model, code)
+	}
+
+	// only well-formed codes are compared, every hash is slow to check
+	code = strings.ToLower(strings.TrimSpace(code))
+	if !recoveryCodeRe.MatchString(code) {
+		return ErrTwoFactorInvalidCode
+	}
+	hashes := splitRecoveryCodes(model.RecoveryCodes)
+	for i, hash := range hashes {
+		if s.encoder.IsValid(hash, code) {
+			model.RecoveryCodes = strings.Join(append(hashes[:i], hashes[i+1:]...), recoveryCodeSep)
+			if err = s.storage.Update(ctx, model); err != nil {
+				return fmt.Errorf("failed to use recovery code. error: %w", err)
+			}
+			return nil
+		}
+	}
+
+	return ErrTwoFactorInvalidCode
+}
+
+// useCode accepts a TOTP code of a time step after the last accepted one, so a code can not be replayed.
+func (s *service) useCode(ctx context.Context, model User, code string) error {
+	step, ok := totp.ValidateStep(model.TwoFactorSecret, code, time.Now(), model.TwoFactorLastStep)
+	if !ok {
+		return ErrTwoFactorInvalidCode
+	}
+
+	used, err := s.storage.UseTwoFactorStep(ctx, model.UUID, step)
+	if err != nil {
+		return fmt.Errorf("failed to use two-factor code. error: %w", err)
+	}
+	if !used {
+		return ErrTwoFactorInvalidCode
+	}
+	return nil
+}
+
+func (s *service) recoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		hash, err := s.encoder.Encode(code)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
+
+func randomRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = recoveryCodeAlphabet[int(b[i])%len(recoveryCodeAlphabet)]
+	}
+	return fmt.Sprintf("%s-%s", b[:5], b[5:]), nil
+}
+
+func splitRecoveryCodes(codes string) []string {
+	if codes == "" {
+		return []string{}
+	}
+	return strings.Split(codes, recoveryCodeSep)
+}
@@ -14,4 +14,8 @@ type Storage interface {
 	FindByEmail(ctx context.Context, email string) (User, error)
 	FindByUUID(ctx context.Context, uuid string) (User, error)
 	Create(ctx context.Context, model User) error
+	Update(ctx context.Context, model User) error
+	// UseTwoFactorStep stores the TOTP time step of an accepted code, it returns false when
+	// the step is not after the stored one, e.g. the code was used by a concurrent request.
+	UseTwoFactorStep(ctx context.Context, uuid string, step int64) (bool, error)
 }
@@ -3,6 +3,7 @@ package component
 import (
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/iagapie/go-spring/modules/sys/view"
+	"github.com/labstack/echo/v4"
 	"net/http"
 	"reflect"
 	"strings"
@@ -52,6 +53,17 @@ type (
 		RegisterComponents() FactoryMap
 	}
 
+	// PluginScope is implemented by views which resolve the "~/" assets of a component against its plugin.
+	PluginScope interface {
+		ForPlugin(code string) view.View
+	}
+
+	// HandlerCaller is implemented by components whose On* handlers are not Go methods, e.g. remote components.
+	HandlerCaller interface {
+		HasHandler(name string) bool
+		CallHandler(name string, c echo.Context) (interface{}, error)
+	}
+
 	CompBase struct {
 		props                 Props
 		externalPropertyNames map[string]string
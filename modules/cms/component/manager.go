@@ -60,11 +60,18 @@ func (m *Manager) MakeComponent(name string, v view.View, props Props) (Componen
 	if fn == nil {
 		return nil, fmt.Errorf("component factory not found \"%s\", check the component plugin", name)
 	}
+
+	m.mu.RLock()
+	info, ok := m.infoMap[name]
+	m.mu.RUnlock()
+	if s, isScope := v.(PluginScope); ok && isScope {
+		v = s.ForPlugin(info.Plugin().Details().Code)
+	}
 	return fn(v, props)
 }
 
 func (m *Manager) Load() {
-	for _, info := range m.pluginManager.All() {
+	for _, info := range m.pluginManager.Enabled() {
 		if regComps, ok := info.Plugin().(PluginRegisterComponents); ok {
 			for code, fn := range regComps.RegisterComponents() {
 				m.RegisterComponent(fn, code, info)
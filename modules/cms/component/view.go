@@ -66,6 +66,9 @@ func (v *viewComps) FindByHandler(handler string) Component {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 	for _, c := range v.comps {
+		if hc, ok := c.(HandlerCaller); ok && hc.HasHandler(handler) {
+			return c
+		}
 		if _, ok := reflect.TypeOf(c).MethodByName(handler); ok {
 			return c
 		}
@@ -2,11 +2,13 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/iagapie/go-spring/modules/cms/component"
 	"github.com/iagapie/go-spring/modules/cms/router"
 	"github.com/iagapie/go-spring/modules/cms/theme"
 	"github.com/iagapie/go-spring/modules/sys/helper"
+	sysRouter "github.com/iagapie/go-spring/modules/sys/router"
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/iagapie/go-spring/modules/sys/view"
 	"github.com/labstack/echo/v4"
@@ -52,7 +54,7 @@ var (
 
 func New(s *spring.Spring, compManager *component.Manager) Controller {
 	t := theme.ActiveTheme()
-	r := router.NewRouter(t)
+	r := router.NewRouter(t, sysRouter.WithBaseURL(s.Cfg.App.URL), sysRouter.WithStrict())
 	stack := component.NewPartialStack()
 
 	ctr := &controller{
@@ -96,9 +98,9 @@ func (ctr *controller) Error(err error, c echo.Context) {
 		status = he.Code
 	}
 
+	// a status without a page of its own is rendered with the /error page, the status is kept
 	page := ctr.router.FindByURL(fmt.Sprintf("/%d", status))
 	if page == nil {
-		status = http.StatusInternalServerError
 		page = ctr.router.FindByURL("/error")
 	}
 
@@ -125,7 +127,16 @@ func (ctr *controller) Run(c echo.Context) error {
 		ctr.router.Reset()
 	}
 
-	page := ctr.router.FindByURL(c.Request().RequestURI)
+	req := c.Request()
+	page, err := ctr.router.FindByRequest(req.Method, c.Scheme(), req.Host, req.RequestURI)
+	if err != nil {
+		var mErr *router.MethodNotAllowedError
+		if errors.As(err, &mErr) {
+			c.Response().Header().Set(echo.HeaderAllow, strings.Join(mErr.Allow, ", "))
+			return echo.NewHTTPError(http.StatusMethodNotAllowed).SetInternal(err)
+		}
+		return err
+	}
 	if page == nil || page.Prop("is_hidden") == "1" {
 		return echo.ErrNotFound
 	}
@@ -423,7 +434,7 @@ func (ctr *controller) getComponentPartial(comp component.Component, name string
 		return v
 	}
 	if p := ctr.compManager.FindPlugin(comp); p != nil {
-		if v := ctr.t.ComponentPartial(p.Dir(), name); v != nil {
+		if v := ctr.t.ComponentPartial(p.FS(), name); v != nil {
 			return v
 		}
 	}
@@ -539,6 +550,10 @@ func (ctr *controller) runAjaxHandler(handler string, c echo.Context) (interface
 }
 
 func callCompMethod(comp component.Component, method string, c echo.Context) (interface{}, error, bool) {
+	if hc, ok := comp.(component.HandlerCaller); ok && hc.HasHandler(method) {
+		r, err := hc.CallHandler(method, c)
+		return r, err, true
+	}
 	if m := reflect.ValueOf(comp).MethodByName(method); m.IsValid() {
 		t := m.Type()
 		if t.NumIn() == 1 && reflect.TypeOf(c).Implements(t.In(0)) {
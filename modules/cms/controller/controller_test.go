@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/datasource"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serve runs a request through a Spring serving pages as the active theme.
+func serve(t *testing.T, pages map[string]string, method, url string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	themesPath := t.TempDir()
+	dir := filepath.Join(themesPath, "test", "pages")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range pages {
+		if err := os.WriteFile(filepath.Join(dir, name+".html"), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := log.New("test")
+	theme.SetThemesPath(themesPath)
+	theme.SetDatasource(datasource.NewFile(l))
+	theme.SetActiveTheme("test")
+
+	s := spring.New(config.Cfg{}, l)
+	s.HTTPErrorHandler = func(err error, c echo.Context) {
+		New(s, nil).Error(err, c)
+	}
+	s.Any("/*", func(c echo.Context) error {
+		return New(s, nil).Run(c)
+	})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(method, url, nil))
+	return rec
+}
+
+func TestErrorKeepsStatusWithoutPage(t *testing.T) {
+	form := "[cfg]\nurl = \"/form\"\nmethods = \"POST\"\n[/cfg]\nform"
+
+	tests := []struct {
+		name  string
+		pages map[string]string
+		body  string
+	}{
+		{
+			name:  "error page",
+			pages: map[string]string{"form": form, "error": "[cfg]\nurl = \"/error\"\n[/cfg]\nerror page"},
+			body:  "error page",
+		},
+		{
+			name:  "no error page",
+			pages: map[string]string{"form": form},
+			body:  ErrHTML,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.pages, http.MethodGet, "/form")
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+			}
+			if allow := rec.Header().Get(echo.HeaderAllow); allow != "POST" {
+				t.Errorf("Allow = %q, want POST", allow)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, tt.body) {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
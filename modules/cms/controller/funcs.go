@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
 	sysRouter "github.com/iagapie/go-spring/modules/sys/router"
 	"github.com/iagapie/go-spring/modules/sys/view"
 	"html/template"
@@ -16,32 +17,59 @@ func funcs(ctr *controller) template.FuncMap {
 		"isPage": func(name string) bool {
 			return strings.EqualFold(ctr.cur.Page.Name(), name)
 		},
-		"pageURL": func(name string, params ...view.Param) string {
-			routerParams := make(sysRouter.Params)
-			for _, p := range params {
-				routerParams[p.Name] = fmt.Sprintf("%v", p.Value)
-			}
-			return ctr.router.FindByPageName(name, routerParams)
+		"pageURL": func(name string, params ...view.Param) (string, error) {
+			return ctr.pageURL(ctr.router.FindByPageName, name, params)
 		},
-		"assets": func(name string) string {
-			uri, _ := ctr.t.Assets()
-			return fmt.Sprintf("%s/%s", uri, name)
+		"absolutePageURL": func(name string, params ...view.Param) (string, error) {
+			return ctr.pageURL(ctr.router.AbsoluteByPageName, name, params)
+		},
+		"assets":       ctr.t.AssetURL,
+		"pluginAssets": ctr.pluginAssetURL,
+		"combine": func(names ...string) (string, error) {
+			return theme.Combine(ctr.s.Assets, ctr.t, names...)
 		},
 		"styles": func() template.HTML {
-			assets, _ := ctr.t.Assets()
-			styles := ctr.cur.Page.Styles(assets)
-			if ctr.cur.Layout != nil {
-				styles = fmt.Sprintf("%s%s", ctr.cur.Layout.Styles(assets), styles)
-			}
-			return template.HTML(styles)
+			return template.HTML(theme.RenderAssets(theme.PlacementHead, ctr.t.Asset, ctr.cur.Layout, ctr.cur.Page))
 		},
 		"scripts": func() template.HTML {
-			assets, _ := ctr.t.Assets()
-			scripts := ctr.cur.Page.Scripts(assets)
-			if ctr.cur.Layout != nil {
-				scripts = fmt.Sprintf("%s%s", ctr.cur.Layout.Scripts(assets), scripts)
-			}
-			return template.HTML(scripts)
+			return template.HTML(theme.RenderAssets(theme.PlacementBodyEnd, ctr.t.Asset, ctr.cur.Layout, ctr.cur.Page))
 		},
 	}
 }
+
+// pageURL fails the template in debug mode when the page is not found or a required param is missing.
+// Otherwise the error is logged and the URL with "default" for the missing params is used.
+func (ctr *controller) pageURL(build func(string, sysRouter.Params) (string, error), name string, params []view.Param) (string, error) {
+	routerParams := make(sysRouter.Params)
+	for _, p := range params {
+		routerParams[p.Name] = fmt.Sprintf("%v", p.Value)
+	}
+
+	url, err := build(name, routerParams)
+	if err != nil {
+		if ctr.s.Cfg.App.Debug {
+			return "", fmt.Errorf("pageURL: %w", err)
+		}
+		ctr.s.Logger.Warnf("pageURL: %v", err)
+	}
+	return url, nil
+}
+
+// pluginAssetURL serves {{ pluginAssets "js/app.js" }} in the partials of a component, and
+// {{ pluginAssets "author_plugin" "js/app.js" }} anywhere else.
+func (ctr *controller) pluginAssetURL(args ...string) (string, error) {
+	switch len(args) {
+	case 1:
+		if ctr.componentCtx == nil {
+			return "", fmt.Errorf("pluginAssets: %s is used outside of a component, pass the plugin code", args[0])
+		}
+		p := ctr.compManager.FindPlugin(ctr.componentCtx)
+		if p == nil {
+			return "", fmt.Errorf("pluginAssets: component %s has no plugin", ctr.componentCtx.Details().Code)
+		}
+		return ctr.t.AssetURL(theme.PluginAssetName(p.Plugin().Details().Code, args[0])), nil
+	case 2:
+		return ctr.t.AssetURL(theme.PluginAssetName(args[0], args[1])), nil
+	}
+	return "", fmt.Errorf("pluginAssets: expected a name, or a plugin code and a name, got %d arguments", len(args))
+}
@@ -0,0 +1,149 @@
+package router
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/iagapie/go-spring/modules/sys/router"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type (
+	// MethodNotAllowedError is returned by FindByRequest when pages match the URL but none accepts the method.
+	MethodNotAllowedError struct {
+		Method string
+		Allow  []string
+	}
+
+	// hostPattern is the compiled host page property, e.g. {sub}.example.com.
+	hostPattern struct {
+		re       *regexp.Regexp
+		names    []string
+		withPort bool
+	}
+)
+
+var (
+	_hosts      sync.Map
+	hostParamRe = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+)
+
+func (e *MethodNotAllowedError) Error() string {
+	return fmt.Sprintf("method %s is not allowed, allowed: %s", e.Method, strings.Join(e.Allow, ", "))
+}
+
+// FindByRequest is FindByURL for the pages whose methods, host and scheme properties accept the request.
+// The params of the host are added to the route params, unless the URL has a param with the same name.
+func (r *Router) FindByRequest(method, scheme, host, url string) (theme.View, error) {
+	var allow []string
+
+	page := r.find(url, func(page theme.View, params router.Params) bool {
+		if s := page.Prop("scheme"); len(s) > 0 && !strings.EqualFold(s, scheme) {
+			return false
+		}
+
+		hostParams, ok := matchHost(page.Prop("host"), host)
+		if !ok {
+			return false
+		}
+
+		if methods := pageMethods(page); !allowed(methods, method) {
+			for _, m := range methods {
+				if !contains(allow, m) {
+					allow = append(allow, m)
+				}
+			}
+			return false
+		}
+
+		for name, value := range hostParams {
+			if _, ok := params[name]; !ok {
+				params[name] = value
+			}
+		}
+		return true
+	})
+
+	if page == nil && len(allow) > 0 {
+		return nil, &MethodNotAllowedError{Method: method, Allow: allow}
+	}
+	return page, nil
+}
+
+// pageMethods reads methods = GET,POST, HEAD goes with GET. Empty means every method.
+func pageMethods(page theme.View) []string {
+	var methods []string
+	for _, m := range strings.Split(page.Prop("methods"), ",") {
+		if m = strings.ToUpper(strings.TrimSpace(m)); len(m) > 0 && !contains(methods, m) {
+			methods = append(methods, m)
+		}
+	}
+	if contains(methods, "GET") && !contains(methods, "HEAD") {
+		methods = append(methods, "HEAD")
+	}
+	return methods
+}
+
+func allowed(methods []string, method string) bool {
+	return len(methods) == 0 || contains(methods, method)
+}
+
+func contains(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHost checks the host of the request against the host page property. The port of the request
+// is ignored unless the property has one.
+func matchHost(pattern, host string) (router.Params, bool) {
+	if len(pattern) == 0 {
+		return nil, true
+	}
+
+	hp := compileHost(pattern)
+	if !hp.withPort {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+
+	m := hp.re.FindStringSubmatch(host)
+	if m == nil {
+		return nil, false
+	}
+
+	params := make(router.Params, len(hp.names))
+	for i, name := range hp.names {
+		params[name] = m[i+1]
+	}
+	return params, true
+}
+
+func compileHost(pattern string) *hostPattern {
+	if hp, ok := _hosts.Load(pattern); ok {
+		return hp.(*hostPattern)
+	}
+
+	hp := &hostPattern{withPort: strings.Contains(hostParamRe.ReplaceAllString(pattern, ""), ":")}
+	b := new(strings.Builder)
+	b.WriteString("(?i)^")
+	last := 0
+	for _, loc := range hostParamRe.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		b.WriteString(`([^.:]+)`)
+		hp.names = append(hp.names, pattern[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString("$")
+	hp.re = regexp.MustCompile(b.String())
+
+	_hosts.Store(pattern, hp)
+	return hp
+}
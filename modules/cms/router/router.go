@@ -1,26 +1,82 @@
 package router
 
 import (
+	"context"
 	"github.com/iagapie/go-spring/modules/cms/theme"
 	"github.com/iagapie/go-spring/modules/sys/router"
+	"sync"
+	"time"
 )
 
-type Router struct {
-	t         theme.Theme
-	sysRouter router.Router
-	params    router.Params
-	url       string
-}
+type (
+	Router struct {
+		t         theme.Theme
+		opts      []router.Option
+		sysRouter router.Router
+		params    router.Params
+		url       string
+	}
+
+	// compiled is the sys router of the active theme, shared by the requests until Reset, a theme switch
+	// or Watch seeing a page file change. version is the PagesVersion it was compiled from.
+	compiled struct {
+		dir       string
+		version   string
+		sysRouter router.Router
+	}
+)
+
+var (
+	_mu       sync.RWMutex
+	_compiled *compiled
+)
 
-func NewRouter(t theme.Theme) *Router {
+// NewRouter passes opts to the sys router of the theme, they have to be the same for every router of the process
+// as the compiled sys router is shared.
+func NewRouter(t theme.Theme, opts ...router.Option) *Router {
 	return &Router{
-		t: t,
+		t:    t,
+		opts: opts,
 	}
 }
 
 func (r *Router) Reset() {
 	r.t.ResetViews()
 	r.sysRouter = nil
+
+	_mu.Lock()
+	_compiled = nil
+	_mu.Unlock()
+}
+
+// Watch drops the compiled router when a page file of its theme is added, removed or modified,
+// the page files are checked every interval until ctx is done.
+func Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_mu.RLock()
+			c := _compiled
+			_mu.RUnlock()
+			if c == nil {
+				continue
+			}
+
+			if t, ok := theme.Get(c.dir); ok && t.PagesVersion() == c.version {
+				continue
+			}
+			_mu.Lock()
+			if _compiled == c {
+				_compiled = nil
+			}
+			_mu.Unlock()
+		}
+	}
 }
 
 func (r *Router) URL() string {
@@ -45,43 +101,78 @@ func (r *Router) Param(name string) string {
 }
 
 func (r *Router) FindByURL(url string) theme.View {
+	return r.find(url, nil)
+}
+
+// find returns the first page matching the url which accept takes. The pages are loaded again
+// when a matching page file is gone.
+func (r *Router) find(url string, accept func(page theme.View, params router.Params) bool) theme.View {
 	r.url = url
 	url = router.NormalizeUrl(url)
 
 	for pass := 1; pass <= 2; pass++ {
-		sr := r.getSysRouter()
-		if sr.Match(url) {
-			r.params = sr.Params()
-			name := sr.Matched()
-
-			if page := r.t.Page(name); page != nil && page.Exists() {
-				return page
+		stale := false
+		name, params, ok := r.getSysRouter().FindFunc(url, func(name string, params router.Params) bool {
+			page := r.t.Page(name)
+			if page == nil || !page.Exists() {
+				stale = true
+				return false
 			}
+			return accept == nil || accept(page, params)
+		})
+		if ok {
+			r.params = params
+			return r.t.Page(name)
+		}
 
-			if pass == 1 {
-				r.Reset()
-			}
+		if !stale || pass == 2 {
+			break
 		}
+		r.Reset()
 	}
 	return nil
 }
 
-func (r *Router) FindByPageName(name string, params router.Params) string {
-	return r.getSysRouter().URL(name, params)
+func (r *Router) FindByPageName(name string, params router.Params) (string, error) {
+	return r.getSysRouter().Build(name, params)
+}
+
+func (r *Router) AbsoluteByPageName(name string, params router.Params) (string, error) {
+	return r.getSysRouter().AbsoluteURL(name, params)
 }
 
+// getSysRouter compiles the pages of the theme once, the requests after it share the compiled router.
 func (r *Router) getSysRouter() router.Router {
 	if r.sysRouter != nil {
 		return r.sysRouter
 	}
 
-	r.sysRouter = router.New()
+	_mu.RLock()
+	c := _compiled
+	_mu.RUnlock()
+	if c != nil && c.dir == r.t.Dir() {
+		r.sysRouter = c.sysRouter
+		return r.sysRouter
+	}
+
+	_mu.Lock()
+	defer _mu.Unlock()
+	if _compiled != nil && _compiled.dir == r.t.Dir() {
+		r.sysRouter = _compiled.sysRouter
+		return r.sysRouter
+	}
+
+	// the version is taken first, so a page changed while compiling makes Watch drop the router
+	version := r.t.PagesVersion()
+	sysRouter := router.New(r.opts...)
 	for name, page := range r.t.Pages() {
 		if pattern := page.Prop("url"); len(pattern) > 0 {
-			r.sysRouter.Route(name, pattern)
+			sysRouter.Route(name, pattern)
 		}
 	}
-	r.sysRouter.Sort()
+	sysRouter.Sort()
 
+	_compiled = &compiled{dir: r.t.Dir(), version: version, sysRouter: sysRouter}
+	r.sysRouter = sysRouter
 	return r.sysRouter
 }
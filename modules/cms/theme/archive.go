@@ -0,0 +1,327 @@
+package theme
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	contentDir    = "_content"
+	cfgFile       = "theme.yml"
+	MaxImportSize = 256 << 20
+)
+
+var (
+	ErrExists  = errors.New("theme already exists")
+	ErrArchive = errors.New("invalid theme archive")
+
+	dirRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+)
+
+type (
+	// Content moves the database-stored content a plugin keeps for a theme in and out of archives.
+	Content interface {
+		ExportThemeContent(ctx context.Context, theme string) ([]byte, error)
+		ImportThemeContent(ctx context.Context, theme string, data []byte) error
+	}
+
+	PluginRegisterThemeContent interface {
+		RegisterThemeContent() Content
+	}
+
+	ContentMap map[string]Content
+
+	ImportOptions struct {
+		// Name installs the theme under another directory than the one in the archive.
+		Name    string
+		Force   bool
+		Content ContentMap
+	}
+)
+
+// Contents collects the theme content of the plugins, keyed by plugin code.
+func Contents(plugins []plugin.Info) ContentMap {
+	contents := make(ContentMap)
+	for _, i := range plugins {
+		if reg, ok := i.Plugin().(PluginRegisterThemeContent); ok {
+			contents[i.Plugin().Details().Code] = reg.RegisterThemeContent()
+		}
+	}
+	return contents
+}
+
+// Export writes the files of the theme under <dir>/ and, when contents is not empty,
+// the database content of each plugin to _content/<code>.json. Parent themes are not included.
+func Export(ctx context.Context, w io.Writer, t Theme, contents ContentMap) error {
+	zw := zip.NewWriter(w)
+
+	root := t.Path()
+	err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		out, err := zw.Create(path.Join(t.Dir(), filepath.ToSlash(rel)))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("theme export: %w", err)
+	}
+
+	for _, code := range contents.codes() {
+		data, err := contents[code].ExportThemeContent(ctx, t.Dir())
+		if err != nil {
+			return fmt.Errorf("theme export: content of %s: %w", code, err)
+		}
+		if data == nil {
+			continue
+		}
+
+		out, err := zw.Create(path.Join(contentDir, code+".json"))
+		if err != nil {
+			return fmt.Errorf("theme export: %w", err)
+		}
+		if _, err = out.Write(data); err != nil {
+			return fmt.Errorf("theme export: %w", err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// Import validates the archive and unpacks it into themesPath. Nothing is written unless the
+// whole archive is valid, an existing theme is replaced only with opts.Force.
+func Import(ctx context.Context, r io.ReaderAt, size int64, themesPath string, opts ImportOptions) (string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrArchive, err)
+	}
+
+	dir, files, contents, err := readArchive(zr)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Name != "" {
+		dir = opts.Name
+	}
+	if !dirRegex.MatchString(dir) {
+		return "", fmt.Errorf("%w: theme directory %q must use a-z, 0-9, _ and -", ErrArchive, dir)
+	}
+
+	if err = validateCfg(files[cfgFile], themesPath, dir); err != nil {
+		return "", err
+	}
+
+	target := filepath.Join(themesPath, dir)
+	if _, err = os.Stat(target); err == nil && !opts.Force {
+		return "", fmt.Errorf("%w: %s, use force to replace it", ErrExists, dir)
+	}
+
+	tmp, err := os.MkdirTemp(themesPath, "."+dir+"-")
+	if err != nil {
+		return "", fmt.Errorf("theme import: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for name, f := range files {
+		if err = extract(f, filepath.Join(tmp, filepath.FromSlash(name))); err != nil {
+			return "", fmt.Errorf("theme import: %s: %w", name, err)
+		}
+	}
+
+	if err = os.RemoveAll(target); err != nil {
+		return "", fmt.Errorf("theme import: %w", err)
+	}
+	if err = os.Rename(tmp, target); err != nil {
+		return "", fmt.Errorf("theme import: %w", err)
+	}
+
+	for _, code := range sortedKeys(contents) {
+		c, ok := opts.Content[code]
+		if !ok {
+			continue
+		}
+		data, err := readAll(contents[code])
+		if err != nil {
+			return dir, fmt.Errorf("theme import: content of %s: %w", code, err)
+		}
+		if err = c.ImportThemeContent(ctx, dir, data); err != nil {
+			return dir, fmt.Errorf("theme import: content of %s: %w", code, err)
+		}
+	}
+
+	return dir, nil
+}
+
+// readArchive splits the entries into the theme files, relative to the theme directory, and the plugin contents.
+func readArchive(zr *zip.Reader) (string, map[string]*zip.File, map[string]*zip.File, error) {
+	var (
+		dir      string
+		total    uint64
+		files    = make(map[string]*zip.File)
+		contents = make(map[string]*zip.File)
+	)
+
+	for _, f := range zr.File {
+		name := f.Name
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		if strings.Contains(name, "\\") || path.IsAbs(name) || path.Clean(name) != name || strings.HasPrefix(name, "../") || name == ".." {
+			return "", nil, nil, fmt.Errorf("%w: unsafe path %q", ErrArchive, name)
+		}
+		if !f.Mode().IsRegular() {
+			return "", nil, nil, fmt.Errorf("%w: %s is not a regular file", ErrArchive, name)
+		}
+
+		total += f.UncompressedSize64
+		if total > MaxImportSize {
+			return "", nil, nil, fmt.Errorf("%w: more than %d bytes uncompressed", ErrArchive, MaxImportSize)
+		}
+
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) != 2 {
+			return "", nil, nil, fmt.Errorf("%w: %s is outside of the theme directory", ErrArchive, name)
+		}
+
+		if parts[0] == contentDir {
+			code := strings.TrimSuffix(parts[1], ".json")
+			if code == parts[1] || strings.Contains(code, "/") {
+				return "", nil, nil, fmt.Errorf("%w: unexpected content file %s", ErrArchive, name)
+			}
+			contents[code] = f
+			continue
+		}
+
+		if dir == "" {
+			dir = parts[0]
+		} else if dir != parts[0] {
+			return "", nil, nil, fmt.Errorf("%w: more than one theme directory (%s, %s)", ErrArchive, dir, parts[0])
+		}
+		files[parts[1]] = f
+	}
+
+	if dir == "" {
+		return "", nil, nil, fmt.Errorf("%w: no theme found", ErrArchive)
+	}
+	if _, ok := files[cfgFile]; !ok {
+		return "", nil, nil, fmt.Errorf("%w: %s/%s is missing", ErrArchive, dir, cfgFile)
+	}
+
+	return dir, files, contents, nil
+}
+
+func validateCfg(f *zip.File, themesPath, dir string) error {
+	b, err := readAll(f)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrArchive, err)
+	}
+
+	var cfg Cfg
+	if err = yaml.UnmarshalStrict(b, &cfg); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrArchive, cfgFile, err)
+	}
+	if strings.TrimSpace(cfg.Name) == "" {
+		return fmt.Errorf("%w: %s: name is required", ErrArchive, cfgFile)
+	}
+	if cfg.Parent != "" {
+		if !dirRegex.MatchString(cfg.Parent) {
+			return fmt.Errorf("%w: %s: parent %q must use a-z, 0-9, _ and -", ErrArchive, cfgFile, cfg.Parent)
+		}
+		if cfg.Parent == dir {
+			return fmt.Errorf("%w: %s: theme %s can not be its own parent", ErrArchive, cfgFile, dir)
+		}
+		if _, err = os.Stat(filepath.Join(themesPath, cfg.Parent, cfgFile)); err != nil {
+			return fmt.Errorf("%w: parent theme %s, import it first", ErrNotFound, cfg.Parent)
+		}
+	}
+	return nil
+}
+
+func extract(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	// the declared size can lie, never copy more than it
+	if _, err = io.Copy(out, io.LimitReader(rc, int64(f.UncompressedSize64))); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func readAll(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, io.LimitReader(rc, int64(f.UncompressedSize64))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (m ContentMap) codes() []string {
+	codes := make([]string, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func sortedKeys(m map[string]*zip.File) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
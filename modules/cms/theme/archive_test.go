@@ -0,0 +1,136 @@
+package theme
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCfg = "name: Demo\n"
+
+type entry struct {
+	name string
+	data string
+	// size is the declared uncompressed size when it is not zero
+	size uint64
+	mode os.FileMode
+}
+
+// archive zips the entries without compression, so a declared size can differ from the data.
+func archive(t *testing.T, entries ...entry) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		h := &zip.FileHeader{
+			Name:               e.name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE([]byte(e.data)),
+			CompressedSize64:   uint64(len(e.data)),
+			UncompressedSize64: uint64(len(e.data)),
+		}
+		if e.size != 0 {
+			h.UncompressedSize64 = e.size
+		}
+		if e.mode != 0 {
+			h.SetMode(e.mode)
+		}
+		w, err := zw.CreateRaw(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestImportRejectsArchive(t *testing.T) {
+	cfg := entry{name: "demo/" + cfgFile, data: testCfg}
+
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{"parent directory", []entry{cfg, {name: "../x", data: "x"}}},
+		{"parent directory inside the theme", []entry{cfg, {name: "demo/../../x", data: "x"}}},
+		{"absolute path", []entry{cfg, {name: "/tmp/x", data: "x"}}},
+		{"backslash", []entry{cfg, {name: `demo\..\..\x`, data: "x"}}},
+		{"two theme directories", []entry{cfg, {name: "other/pages/home.html", data: "x"}}},
+		{"file outside of the theme directory", []entry{cfg, {name: "x.html", data: "x"}}},
+		{"missing theme.yml", []entry{{name: "demo/pages/home.html", data: "x"}}},
+		{"empty", nil},
+		{"oversize entry", []entry{cfg, {name: "demo/big.bin", data: "x", size: MaxImportSize + 1}}},
+		{"oversize total", []entry{cfg, {name: "demo/a.bin", data: "x", size: MaxImportSize / 2}, {name: "demo/b.bin", data: "x", size: MaxImportSize / 2}}},
+		{"symlink", []entry{cfg, {name: "demo/link", data: "/etc/passwd", mode: os.ModeSymlink | 0777}}},
+		{"nested content", []entry{cfg, {name: contentDir + "/a/b.json", data: "{}"}}},
+		{"invalid theme.yml", []entry{{name: "demo/" + cfgFile, data: "title: x\n"}}},
+		{"invalid directory", []entry{{name: "Demo/" + cfgFile, data: testCfg}}},
+		{"invalid parent", []entry{{name: "demo/" + cfgFile, data: testCfg + "parent: ../x\n"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			themesPath := filepath.Join(base, "themes")
+			if err := os.Mkdir(themesPath, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			r := archive(t, tt.entries...)
+			if _, err := Import(context.Background(), r, r.Size(), themesPath, ImportOptions{}); !errors.Is(err, ErrArchive) {
+				t.Fatalf("Import error = %v, want %v", err, ErrArchive)
+			}
+
+			if names, _ := os.ReadDir(themesPath); len(names) != 0 {
+				t.Errorf("Import left %v in the themes path", names)
+			}
+			if names, _ := os.ReadDir(base); len(names) != 1 {
+				t.Errorf("Import wrote %v next to the themes path", names)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	themesPath := t.TempDir()
+	r := archive(t,
+		entry{name: "demo/" + cfgFile, data: testCfg},
+		entry{name: "demo/pages/home.html", data: "home"},
+		// the declared size is kept even when the data is longer
+		entry{name: "demo/assets/app.js", data: "0123456789", size: 4},
+		entry{name: contentDir + "/blog.json", data: "{}"},
+	)
+
+	dir, err := Import(context.Background(), r, r.Size(), themesPath, ImportOptions{})
+	if err != nil || dir != "demo" {
+		t.Fatalf("Import = %q %v, want demo", dir, err)
+	}
+
+	for name, want := range map[string]string{"pages/home.html": "home", "assets/app.js": "0123", cfgFile: testCfg} {
+		b, err := os.ReadFile(filepath.Join(themesPath, "demo", filepath.FromSlash(name)))
+		if err != nil || string(b) != want {
+			t.Errorf("%s = %q %v, want %q", name, b, err, want)
+		}
+	}
+	if _, err = os.Stat(filepath.Join(themesPath, "demo", contentDir)); !os.IsNotExist(err) {
+		t.Errorf("content was extracted into the theme: %v", err)
+	}
+
+	if _, err = Import(context.Background(), r, r.Size(), themesPath, ImportOptions{}); !errors.Is(err, ErrExists) {
+		t.Errorf("second Import error = %v, want %v", err, ErrExists)
+	}
+	if dir, err = Import(context.Background(), r, r.Size(), themesPath, ImportOptions{Force: true}); err != nil {
+		t.Errorf("Import with force = %q %v", dir, err)
+	}
+}
@@ -0,0 +1,197 @@
+package theme
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/view"
+	"html"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	AssetCSS AssetType = iota
+	AssetJS
+)
+
+const (
+	PlacementDefault Placement = ""
+	PlacementHead    Placement = "head"
+	PlacementBodyEnd Placement = "body-end"
+)
+
+type (
+	AssetType int
+	Placement string
+
+	// Asset is a file, a URL or an inline snippet added to a view. Assets with a lower priority
+	// come first, equal priorities keep the order they were added in.
+	Asset struct {
+		Type      AssetType
+		Name      string
+		Inline    string
+		Priority  int
+		Placement Placement
+		Attrs     []view.Param
+		seq       uint64
+	}
+
+	// AssetResolver returns the URL of an asset name and its local file.
+	AssetResolver func(name string) (uri string, file string)
+
+	integrity struct {
+		modTime time.Time
+		hash    string
+	}
+)
+
+var (
+	_seq       uint64
+	_integrity sync.Map
+)
+
+// AddCSS adds a stylesheet to the head.
+func (v *themeView) AddCSS(name string, attrs ...view.Param) {
+	v.AddAsset(Asset{Type: AssetCSS, Name: name, Attrs: attrs})
+}
+
+// AddJS adds a script before the end of the body.
+func (v *themeView) AddJS(name string, attrs ...view.Param) {
+	v.AddAsset(Asset{Type: AssetJS, Name: name, Attrs: attrs})
+}
+
+func (v *themeView) AddAsset(a Asset) {
+	a.seq = atomic.AddUint64(&_seq, 1)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.assets = append(v.assets, a)
+}
+
+func (v *themeView) Assets() []Asset {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	assets := make([]Asset, len(v.assets))
+	copy(assets, v.assets)
+	return assets
+}
+
+func (a Asset) placement() Placement {
+	if a.Placement != PlacementDefault {
+		return a.Placement
+	}
+	if a.Type == AssetCSS {
+		return PlacementHead
+	}
+	return PlacementBodyEnd
+}
+
+// RenderAssets prints the tags of the assets placed at placement, collected from all views (layout, page, ...)
+// and deduplicated by URL, or by content for inline snippets. Local files get an SRI integrity attribute.
+func RenderAssets(placement Placement, resolve AssetResolver, views ...View) string {
+	var assets []Asset
+	for _, v := range views {
+		if v == nil {
+			continue
+		}
+		for _, a := range v.Assets() {
+			if a.placement() == placement {
+				assets = append(assets, a)
+			}
+		}
+	}
+
+	sort.SliceStable(assets, func(i, j int) bool {
+		if assets[i].Priority != assets[j].Priority {
+			return assets[i].Priority < assets[j].Priority
+		}
+		return assets[i].seq < assets[j].seq
+	})
+
+	b := new(strings.Builder)
+	seen := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		if a.Inline != "" {
+			sum := sha256.Sum256([]byte(a.Inline))
+			key := fmt.Sprintf("%d:inline:%s", a.Type, hex.EncodeToString(sum[:]))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if a.Type == AssetCSS {
+				b.WriteString(fmt.Sprintf("<style%s>%s</style>\n", toAttrs(a.Attrs), a.Inline))
+			} else {
+				b.WriteString(fmt.Sprintf("<script%s>%s</script>\n", toAttrs(a.Attrs), a.Inline))
+			}
+			continue
+		}
+
+		uri, file := a.Name, ""
+		if !isURL(a.Name) {
+			uri, file = resolve(a.Name)
+		}
+
+		key := fmt.Sprintf("%d:%s", a.Type, uri)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		attrs := toAttrs(a.Attrs)
+		if hash := fileIntegrity(file); hash != "" && !hasAttr(a.Attrs, "integrity") {
+			attrs = fmt.Sprintf(" integrity=\"%s\"%s", hash, attrs)
+		}
+
+		if a.Type == AssetCSS {
+			b.WriteString(fmt.Sprintf("<link rel=\"stylesheet\" href=\"%s\"%s>\n", html.EscapeString(uri), attrs))
+		} else {
+			b.WriteString(fmt.Sprintf("<script src=\"%s\"%s></script>\n", html.EscapeString(uri), attrs))
+		}
+	}
+	return b.String()
+}
+
+// fileIntegrity returns the sha384 SRI hash of the file, cached until the file changes.
+func fileIntegrity(file string) string {
+	if file == "" {
+		return ""
+	}
+
+	fi, err := os.Stat(file)
+	if err != nil {
+		return ""
+	}
+	if cached, ok := _integrity.Load(file); ok && cached.(integrity).modTime.Equal(fi.ModTime()) {
+		return cached.(integrity).hash
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	sum := sha512.Sum384(b)
+	hash := "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+	_integrity.Store(file, integrity{modTime: fi.ModTime(), hash: hash})
+	return hash
+}
+
+// isURL tells names which are used as they are from names of theme assets.
+func isURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "/")
+}
+
+func hasAttr(attrs []view.Param, name string) bool {
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package theme
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/asset"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	combineRegex = regexp.MustCompile(`\{\{-?\s*combine((?:\s+"[^"]+")+)\s*-?\}\}`)
+	argRegex     = regexp.MustCompile(`"([^"]+)"`)
+)
+
+// Combine bundles the assets of the theme and returns the URL of the bundle.
+func Combine(combiner *asset.Combiner, t Theme, names ...string) (string, error) {
+	sources := make([]asset.Source, 0, len(names))
+	for _, name := range names {
+		uri, path := t.Asset(name)
+		sources = append(sources, asset.Source{Path: path, URL: uri})
+	}
+
+	file, err := combiner.Combine(t.Dir(), sources)
+	if err != nil {
+		return "", err
+	}
+
+	uri, _ := t.Assets()
+	return fmt.Sprintf("%s/%s/%s", uri, asset.CombineDir, file), nil
+}
+
+// CombineCalls finds the {{ combine "..." }} calls with literal arguments in the templates of the theme and its parents.
+func CombineCalls(t Theme) ([][]string, error) {
+	chain, err := t.Chain()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var calls [][]string
+
+	for _, c := range chain {
+		for _, dir := range []string{dLayouts, dPages, dPartials} {
+			root := filepath.Join(c.Path(), dir)
+			if _, err := os.Stat(root); err != nil {
+				continue
+			}
+
+			err := filepath.Walk(root, func(file string, fi os.FileInfo, err error) error {
+				if err != nil || fi.IsDir() || filepath.Ext(file) != ".html" {
+					return err
+				}
+
+				b, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+
+				for _, m := range combineRegex.FindAllSubmatch(b, -1) {
+					var names []string
+					for _, arg := range argRegex.FindAllSubmatch(m[1], -1) {
+						names = append(names, string(arg[1]))
+					}
+					if key := strings.Join(names, "|"); !seen[key] {
+						seen[key] = true
+						calls = append(calls, names)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(calls, func(i, j int) bool {
+		return strings.Join(calls[i], "|") < strings.Join(calls[j], "|")
+	})
+	return calls, nil
+}
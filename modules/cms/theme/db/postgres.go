@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/theme"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const activeThemeKey = "cms.active_theme"
+
+var _ theme.Storage = &storage{}
+
+type (
+	setting struct {
+		Key       string `gorm:"primaryKey;size:255"`
+		Value     string
+		UpdatedAt time.Time
+	}
+
+	storage struct {
+		db  *postgresdb.Database
+		log *logrus.Entry
+	}
+)
+
+func (setting) TableName() string {
+	return "settings"
+}
+
+func NewStorage(postgres *postgresdb.Database, log *logrus.Entry) theme.Storage {
+	return &storage{
+		db:  postgres,
+		log: log,
+	}
+}
+
+func (s *storage) Active(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var model setting
+	if err := s.db.WithContext(ctx).Where("key = ?", activeThemeKey).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	return model.Value, nil
+}
+
+func (s *storage) SetActive(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&setting{Key: activeThemeKey, Value: name, UpdatedAt: time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+
+	s.log.Tracef("Saved active theme: %s.\n", name)
+
+	return nil
+}
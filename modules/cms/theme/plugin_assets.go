@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/iagapie/go-spring/modules/sys/view"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// pluginPrefix starts the asset names of a plugin: a component adds "~/js/app.js",
+// which is stored as "~<plugin code>/js/app.js".
+const pluginPrefix = "~"
+
+type (
+	pluginAssets struct {
+		uri       string
+		namespace string
+		dir       string
+	}
+
+	// pluginView is the view given to the components of a plugin, it scopes their "~/" assets to the plugin.
+	pluginView struct {
+		*themeView
+		code string
+	}
+)
+
+var _plugins sync.Map
+
+// RegisterPlugins makes the assets/ directories of the plugins resolvable with "~<code>/<name>".
+func RegisterPlugins(plugins []plugin.Info) {
+	for _, i := range plugins {
+		if i.AssetsFS() == nil {
+			continue
+		}
+
+		a := pluginAssets{uri: i.AssetsURI(), namespace: i.Namespace()}
+		if dir := i.Dir(); dir != "" && helper.FileExists(filepath.Join(dir, dAssets)) {
+			a.dir = filepath.Join(dir, dAssets)
+		}
+		_plugins.Store(i.Plugin().Details().Code, a)
+	}
+}
+
+// PluginAssetName returns the name Asset resolves against the assets of the plugin.
+func PluginAssetName(code, name string) string {
+	return pluginPrefix + code + "/" + strings.TrimPrefix(name, "/")
+}
+
+// pluginAsset resolves "~<code>/<name>". A theme file at assets/plugins/<author>/<name>/<name>
+// overrides the file of the plugin.
+func (t *theme) pluginAsset(name string) (uri string, path string, ok bool) {
+	parts := strings.SplitN(strings.TrimPrefix(name, pluginPrefix), "/", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	v, found := _plugins.Load(parts[0])
+	if !found {
+		return "", "", false
+	}
+	a := v.(pluginAssets)
+
+	override := "plugins/" + a.namespace + "/" + parts[1]
+	for _, c := range t.chain() {
+		u, p := c.Assets()
+		if helper.FileExists(filepath.Join(p, override)) {
+			return u + "/" + override, filepath.Join(p, override), true
+		}
+	}
+
+	uri = a.uri + "/" + parts[1]
+	if a.dir != "" {
+		path = filepath.Join(a.dir, filepath.FromSlash(parts[1]))
+	}
+	return uri, path, true
+}
+
+// ForPlugin implements component.PluginScope.
+func (v *themeView) ForPlugin(code string) view.View {
+	return &pluginView{themeView: v, code: code}
+}
+
+func (v *pluginView) AddCSS(name string, attrs ...view.Param) {
+	v.AddAsset(Asset{Type: AssetCSS, Name: name, Attrs: attrs})
+}
+
+func (v *pluginView) AddJS(name string, attrs ...view.Param) {
+	v.AddAsset(Asset{Type: AssetJS, Name: name, Attrs: attrs})
+}
+
+func (v *pluginView) AddAsset(a Asset) {
+	if strings.HasPrefix(a.Name, pluginPrefix+"/") {
+		a.Name = PluginAssetName(v.code, a.Name[len(pluginPrefix)+1:])
+	}
+	v.themeView.AddAsset(a)
+}
@@ -0,0 +1,87 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("theme not found")
+
+// Storage persists the name of the active theme. An empty name means it was never set
+// and the theme from the configuration is used.
+type Storage interface {
+	Active(ctx context.Context) (string, error)
+	SetActive(ctx context.Context, name string) error
+}
+
+// Get returns the theme of the directory in the themes path, the same themes as Themes lists.
+func Get(name string) (Theme, bool) {
+	if len(_themesPath) == 0 || len(name) == 0 || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return nil, false
+	}
+	if fi, err := os.Stat(filepath.Join(_themesPath, name)); err != nil || !fi.IsDir() {
+		return nil, false
+	}
+	return New(_themesPath, name, _ds), true
+}
+
+// Use checks the theme, persists it as the active one and switches to it.
+func Use(ctx context.Context, storage Storage, name string) error {
+	t, ok := Get(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if _, err := t.Cfg(); err != nil {
+		return fmt.Errorf("theme %s has an invalid theme.yml: %w", name, err)
+	}
+	if _, err := t.Chain(); err != nil {
+		return err
+	}
+
+	if err := storage.SetActive(ctx, name); err != nil {
+		return err
+	}
+
+	SetActiveTheme(name)
+	return nil
+}
+
+// Sync switches to the persisted active theme. A persisted theme which no longer exists is ignored.
+func Sync(ctx context.Context, storage Storage) error {
+	name, err := storage.Active(ctx)
+	if err != nil {
+		return err
+	}
+	if name == "" || name == ActiveThemeName() {
+		return nil
+	}
+	if _, ok := Get(name); !ok {
+		return fmt.Errorf("persisted %w: %s", ErrNotFound, name)
+	}
+
+	SetActiveTheme(name)
+	return nil
+}
+
+// Watch syncs the active theme until ctx is done, so a switch made on one instance reaches the others.
+func Watch(ctx context.Context, storage Storage, interval time.Duration, log echo.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := Sync(ctx, storage); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
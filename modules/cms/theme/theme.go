@@ -1,11 +1,17 @@
 package theme
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/iagapie/go-spring/modules/sys/datasource"
 	"github.com/iagapie/go-spring/modules/sys/helper"
 	"html/template"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 type (
@@ -15,6 +21,7 @@ type (
 		Homepage     string `env:"THEME_HOMEPAGE" yaml:"homepage" json:"homepage"`
 		Description  string `env:"THEME_DESCRIPTION" yaml:"description" json:"description"`
 		PreviewImage string `env-default:"assets/images/preview.png" env:"THEME_PREVIEW_IMAGE" yaml:"preview_image" json:"preview_image"`
+		Parent       string `yaml:"parent" json:"parent,omitempty"`
 	}
 
 	Theme interface {
@@ -22,14 +29,21 @@ type (
 		Dir() string
 		Path() string
 		Cfg() (Cfg, error)
+		Parent() Theme
+		Chain() ([]Theme, error)
 		Assets() (uri string, path string)
+		Asset(name string) (uri string, path string)
+		AssetURL(name string) string
+		MailPath() string
 		Funcs(funcs template.FuncMap)
 		ResetViews()
 		Pages() ViewMap
+		// PagesVersion changes whenever a page file of the chain is added, removed or modified.
+		PagesVersion() string
 		Page(name string) View
 		Layout(name string) View
 		Partial(name string) View
-		ComponentPartial(pluginDir, name string) View
+		ComponentPartial(pluginFS fs.FS, name string) View
 	}
 
 	theme struct {
@@ -37,8 +51,12 @@ type (
 		dir        string
 		datasource datasource.Datasource
 		pages      ViewMap
+		allPages   bool
 		layouts    ViewMap
 		partials   ViewMap
+		parentOnce sync.Once
+		parent     Theme
+		parentErr  error
 	}
 )
 
@@ -48,9 +66,14 @@ const (
 	dPages      = "pages"
 	dComponents = "components"
 	dAssets     = "assets"
+	dMail       = "mail"
+
+	// AssetsRoute matches the URIs returned by Assets, the theme directory is the :theme param.
+	AssetsRoute = "/themes/:theme/" + dAssets
 )
 
 var (
+	_mu          sync.RWMutex
 	_activeTheme string
 	_themesPath  string
 	_ds          datasource.Datasource
@@ -60,10 +83,19 @@ func SetThemesPath(themesPath string) {
 	_themesPath = themesPath
 }
 
+// SetActiveTheme switches the theme used by ActiveTheme, it is safe to call while serving requests.
 func SetActiveTheme(activeTheme string) {
+	_mu.Lock()
+	defer _mu.Unlock()
 	_activeTheme = activeTheme
 }
 
+func ActiveThemeName() string {
+	_mu.RLock()
+	defer _mu.RUnlock()
+	return _activeTheme
+}
+
 func SetDatasource(ds datasource.Datasource) {
 	_ds = ds
 }
@@ -79,16 +111,23 @@ func Themes() map[string]Theme {
 	}
 	for _, p := range dirs {
 		name := filepath.Base(p)
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if fi, err := os.Stat(p); err != nil || !fi.IsDir() {
+			continue
+		}
 		themes[name] = New(_themesPath, name, _ds)
 	}
 	return themes
 }
 
 func ActiveTheme() Theme {
-	if len(_activeTheme) == 0 {
+	name := ActiveThemeName()
+	if len(name) == 0 {
 		return nil
 	}
-	return New(_themesPath, _activeTheme, _ds)
+	return New(_themesPath, name, _ds)
 }
 
 func New(basePath, dir string, ds datasource.Datasource) Theme {
@@ -102,7 +141,7 @@ func New(basePath, dir string, ds datasource.Datasource) Theme {
 }
 
 func (t *theme) IsActive() bool {
-	return t.Dir() == _activeTheme
+	return t.Dir() == ActiveThemeName()
 }
 
 func (t *theme) Dir() string {
@@ -119,12 +158,81 @@ func (t *theme) Cfg() (Cfg, error) {
 	return cfg, err
 }
 
+// Parent returns the theme named by parent in theme.yml, or nil. The parent has to be a theme directory
+// name, a path would let the theme read and serve files outside the themes path.
+func (t *theme) Parent() Theme {
+	t.parentOnce.Do(func() {
+		if cfg, err := t.Cfg(); err == nil && cfg.Parent != "" {
+			if !dirRegex.MatchString(cfg.Parent) {
+				t.parentErr = fmt.Errorf("theme %s: parent %q must use a-z, 0-9, _ and -", t.Dir(), cfg.Parent)
+				return
+			}
+			t.parent = New(t.basePath, cfg.Parent, t.datasource)
+		}
+	})
+	return t.parent
+}
+
+// Chain returns the theme followed by its parents, the nearest first.
+func (t *theme) Chain() ([]Theme, error) {
+	chain := []Theme{t}
+	seen := map[string]bool{t.Dir(): true}
+	for cur := Theme(t); cur.Parent() != nil; cur = cur.Parent() {
+		parent := cur.Parent()
+		if seen[parent.Dir()] {
+			return chain, fmt.Errorf("theme %s: parent cycle at %s", t.Dir(), parent.Dir())
+		}
+		if fi, err := os.Stat(parent.Path()); err != nil || !fi.IsDir() {
+			return chain, fmt.Errorf("%w: %s, parent of %s", ErrNotFound, parent.Dir(), cur.Dir())
+		}
+		seen[parent.Dir()] = true
+		chain = append(chain, parent)
+	}
+	if last, ok := chain[len(chain)-1].(*theme); ok && last.parentErr != nil {
+		return chain, last.parentErr
+	}
+	return chain, nil
+}
+
+// chain is Chain without the broken tail.
+func (t *theme) chain() []Theme {
+	chain, _ := t.Chain()
+	return chain
+}
+
 func (t *theme) Assets() (uri string, path string) {
 	uri = fmt.Sprintf("/themes/%s/%s", t.dir, dAssets)
 	path = fmt.Sprintf("%s/%s", t.Path(), dAssets)
 	return
 }
 
+// Asset returns the URL and the file of the asset from the nearest theme in the chain having it,
+// or from the plugin for "~<code>/<name>".
+func (t *theme) Asset(name string) (uri string, path string) {
+	if strings.HasPrefix(name, pluginPrefix) {
+		if u, p, ok := t.pluginAsset(name); ok {
+			return u, p
+		}
+	}
+	for _, c := range t.chain() {
+		u, p := c.Assets()
+		if helper.FileExists(filepath.Join(p, name)) {
+			return fmt.Sprintf("%s/%s", u, name), filepath.Join(p, name)
+		}
+	}
+	u, p := t.Assets()
+	return fmt.Sprintf("%s/%s", u, name), filepath.Join(p, name)
+}
+
+func (t *theme) AssetURL(name string) string {
+	uri, _ := t.Asset(name)
+	return uri
+}
+
+func (t *theme) MailPath() string {
+	return fmt.Sprintf("%s/%s", t.Path(), dMail)
+}
+
 func (t *theme) Funcs(funcs template.FuncMap) {
 	t.datasource.Funcs(funcs)
 }
@@ -132,20 +240,46 @@ func (t *theme) Funcs(funcs template.FuncMap) {
 func (t *theme) ResetViews() {
 	t.layouts = make(ViewMap)
 	t.pages = make(ViewMap)
+	t.allPages = false
 	t.partials = make(ViewMap)
 }
 
 func (t *theme) Pages() ViewMap {
-	if len(t.pages) == 0 {
-		for name, v := range t.datasource.Select(fmt.Sprintf("%s/%s", t.Path(), dPages), "html") {
-			t.pages[name] = newView(v)
+	if !t.allPages {
+		chain := t.chain()
+		for i := len(chain) - 1; i >= 0; i-- {
+			for name, v := range t.datasource.Select(fmt.Sprintf("%s/%s", chain[i].Path(), dPages), "html") {
+				t.pages[name] = newView(v)
+			}
 		}
+		t.allPages = true
 	}
 	return t.pages
 }
 
+func (t *theme) PagesVersion() string {
+	h := sha256.New()
+	for _, c := range t.chain() {
+		files, _ := filepath.Glob(fmt.Sprintf("%s/%s/*.html", c.Path(), dPages))
+		for _, file := range files {
+			if fi, err := os.Stat(file); err == nil {
+				fmt.Fprintf(h, "%s|%d|%d\n", file, fi.Size(), fi.ModTime().UnixNano())
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Page loads only the requested page until all pages are needed.
 func (t *theme) Page(name string) View {
-	if v, ok := t.Pages()[name]; ok {
+	if v, ok := t.pages[name]; ok {
+		return v
+	}
+	if t.allPages || !strings.HasSuffix(name, ".html") {
+		return nil
+	}
+	if v := t.selectOne(dPages, strings.TrimSuffix(name, ".html")); v != nil {
+		t.pages[name] = v
 		return v
 	}
 	return nil
@@ -155,9 +289,9 @@ func (t *theme) Layout(name string) View {
 	if v, ok := t.layouts[name]; ok {
 		return v
 	}
-	if v := t.datasource.SelectOne(fmt.Sprintf("%s/%s", t.Path(), dLayouts), name, "html"); v != nil {
-		t.layouts[name] = newView(v)
-		return t.layouts[name]
+	if v := t.selectOne(dLayouts, name); v != nil {
+		t.layouts[name] = v
+		return v
 	}
 	return nil
 }
@@ -166,20 +300,38 @@ func (t *theme) Partial(name string) View {
 	if v, ok := t.partials[name]; ok {
 		return v
 	}
-	if v := t.datasource.SelectOne(fmt.Sprintf("%s/%s", t.Path(), dPartials), name, "html"); v != nil {
-		t.partials[name] = newView(v)
-		return t.partials[name]
+	if v := t.selectOne(dPartials, name); v != nil {
+		t.partials[name] = v
+		return v
 	}
 	return nil
 }
 
-func (t *theme) ComponentPartial(pluginDir, name string) View {
+func (t *theme) ComponentPartial(pluginFS fs.FS, name string) View {
 	if p := t.Partial(name); p != nil {
 		return p
 	}
-	if v := t.datasource.SelectOne(fmt.Sprintf("%s/%s", pluginDir, dComponents), name, "html"); v != nil {
+	if pluginFS == nil {
+		return nil
+	}
+	if v := t.datasource.SelectOneFS(pluginFS, dComponents, name, "html"); v != nil {
 		t.partials[name] = newView(v)
 		return t.partials[name]
 	}
 	return nil
 }
+
+// selectOne loads the view from the nearest theme in the chain having the file.
+func (t *theme) selectOne(dir, name string) View {
+	for _, c := range t.chain() {
+		path := fmt.Sprintf("%s/%s", c.Path(), dir)
+		if !helper.FileExists(fmt.Sprintf("%s/%s.html", path, name)) {
+			continue
+		}
+		if v := t.datasource.SelectOne(path, name, "html"); v != nil {
+			return newView(v)
+		}
+		return nil
+	}
+	return nil
+}
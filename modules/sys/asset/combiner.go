@@ -0,0 +1,197 @@
+package asset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/labstack/echo/v4"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	CombineDir = "combine"
+	immutable  = "public, max-age=31536000, immutable"
+)
+
+var (
+	urlRegex    = regexp.MustCompile(`url\(\s*(['"]?)([^'")]+)(['"]?)\s*\)`)
+	bundleRegex = regexp.MustCompile(`^[0-9a-f]{16}\.(css|js)$`)
+)
+
+type (
+	// Source is an asset file and the URL it is served at, the URL is needed to rewrite relative url() in CSS.
+	Source struct {
+		Path string
+		URL  string
+	}
+
+	bundle struct {
+		file    string
+		modTime map[string]time.Time
+	}
+
+	Combiner struct {
+		cfg     config.Assets
+		log     echo.Logger
+		mu      sync.Mutex
+		bundles map[string]bundle
+	}
+)
+
+func NewCombiner(cfg config.Assets, log echo.Logger) *Combiner {
+	return &Combiner{
+		cfg:     cfg,
+		log:     log,
+		bundles: make(map[string]bundle),
+	}
+}
+
+// Combine concatenates and minifies the sources into <cache_path>/<group>/<hash>.<ext> and returns the file name.
+// The bundle is rebuilt only when one of the sources changed.
+func (c *Combiner) Combine(group string, sources []Source) (string, error) {
+	if len(sources) == 0 {
+		return "", fmt.Errorf("asset combine: no files")
+	}
+
+	ext := path.Ext(sources[0].Path)
+	if ext != ".css" && ext != ".js" {
+		return "", fmt.Errorf("asset combine: only .css and .js files can be combined, got %s", sources[0].Path)
+	}
+
+	keys := make([]string, 0, len(sources))
+	modTime := make(map[string]time.Time, len(sources))
+	for _, src := range sources {
+		if path.Ext(src.Path) != ext {
+			return "", fmt.Errorf("asset combine: %s and %s have different types", sources[0].Path, src.Path)
+		}
+		fi, err := os.Stat(src.Path)
+		if err != nil {
+			return "", fmt.Errorf("asset combine: %w", err)
+		}
+		keys = append(keys, src.Path)
+		modTime[src.Path] = fi.ModTime()
+	}
+	key := group + "|" + strings.Join(keys, "|")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b, ok := c.bundles[key]; ok && sameModTime(b.modTime, modTime) && helper.FileExists(c.path(group, b.file)) {
+		return b.file, nil
+	}
+
+	var buf bytes.Buffer
+	for _, src := range sources {
+		b, err := os.ReadFile(src.Path)
+		if err != nil {
+			return "", fmt.Errorf("asset combine: %w", err)
+		}
+		if ext == ".css" {
+			b = rewriteURLs(b, src.URL)
+			if c.cfg.Minify {
+				b = MinifyCSS(b)
+			}
+			buf.Write(b)
+			buf.WriteByte('\n')
+		} else {
+			if c.cfg.Minify {
+				b = MinifyJS(b)
+			}
+			buf.Write(b)
+			buf.WriteString(";\n")
+		}
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	file := hex.EncodeToString(sum[:])[:16] + ext
+
+	target := c.path(group, file)
+	if !helper.FileExists(target) {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return "", fmt.Errorf("asset combine: %w", err)
+		}
+		if err := os.WriteFile(target, buf.Bytes(), 0644); err != nil {
+			return "", fmt.Errorf("asset combine: %w", err)
+		}
+		if err := writeGzip(target+".gz", buf.Bytes()); err != nil {
+			return "", fmt.Errorf("asset combine: %w", err)
+		}
+		c.log.Debugf("asset combine: %s/%s was built from %d files", group, file, len(sources))
+	}
+
+	c.bundles[key] = bundle{file: file, modTime: modTime}
+	return file, nil
+}
+
+// Serve returns the handler for GET .../combine/:file of the group, the bundles never change so
+// they are cached for a year.
+func (c *Combiner) Serve(group string) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		file := ctx.Param("file")
+		if !bundleRegex.MatchString(file) {
+			return echo.ErrNotFound
+		}
+
+		return serveFile(ctx, os.DirFS(filepath.Join(c.cfg.CachePath, group)), file, immutable)
+	}
+}
+
+func (c *Combiner) path(group, file string) string {
+	return filepath.Join(c.cfg.CachePath, group, file)
+}
+
+// rewriteURLs makes the relative url() of a stylesheet absolute, the bundle is served from another directory.
+func rewriteURLs(b []byte, fileURL string) []byte {
+	base := path.Dir(fileURL)
+	return urlRegex.ReplaceAllFunc(b, func(m []byte) []byte {
+		parts := urlRegex.FindSubmatch(m)
+		ref := string(parts[2])
+		if strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "#") || strings.Contains(ref, ":") {
+			return m
+		}
+
+		suffix := ""
+		if i := strings.IndexAny(ref, "?#"); i != -1 {
+			ref, suffix = ref[:i], ref[i:]
+		}
+		return []byte(fmt.Sprintf("url(%s%s%s%s)", parts[1], path.Join(base, ref), suffix, parts[3]))
+	})
+}
+
+func sameModTime(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, t := range a {
+		if !t.Equal(b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeGzip stores the precompressed sibling served to clients accepting gzip.
+func writeGzip(file string, b []byte) error {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err = zw.Write(b); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
@@ -0,0 +1,255 @@
+package asset
+
+import (
+	"bytes"
+	"strings"
+)
+
+// regexpKeywords may be followed by a regular expression literal, after any other word a / is a division.
+var regexpKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true, "delete": true,
+	"void": true, "throw": true, "case": true, "do": true, "else": true, "yield": true, "await": true,
+}
+
+// MinifyCSS drops comments and collapses whitespace. Spaces are only removed next to { } ; , and >
+// where they never change the meaning, e.g. "a :hover" and "calc(1px + 2px)" are kept as they are.
+func MinifyCSS(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src))
+
+	space := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end == -1 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+			space = true
+			continue
+		case c == '"' || c == '\'':
+			flushSpace(&out, &space, c)
+			i = copyString(&out, src, i, c)
+			continue
+		case isSpace(c):
+			space = true
+			continue
+		}
+
+		if strings.IndexByte("{};,>", c) != -1 {
+			space = false
+			trimTrailing(&out, ' ')
+			if c == '}' {
+				trimTrailing(&out, ';')
+			}
+			out.WriteByte(c)
+			continue
+		}
+
+		flushSpace(&out, &space, c)
+		out.WriteByte(c)
+	}
+
+	return bytes.TrimSpace(out.Bytes())
+}
+
+// MinifyJS is deliberately conservative: it drops comments, indentation and blank lines but keeps
+// line breaks, so automatic semicolon insertion works exactly as in the source.
+func MinifyJS(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src))
+
+	space := false
+	prev := byte('\n') // last significant character written
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			i--
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end == -1 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+			space = true
+			continue
+		case c == '/' && regexpAllowed(out.Bytes(), prev):
+			flushSpace(&out, &space, c)
+			i = copyRegexp(&out, src, i)
+			prev = '/'
+			continue
+		case c == '"' || c == '\'':
+			flushSpace(&out, &space, c)
+			i = copyString(&out, src, i, c)
+			prev = c
+			continue
+		case c == '`':
+			flushSpace(&out, &space, c)
+			i = copyTemplate(&out, src, i)
+			prev = c
+			continue
+		case c == '\n':
+			space = false
+			trimTrailing(&out, ' ')
+			if out.Len() > 0 && prev != '\n' {
+				out.WriteByte('\n')
+				prev = '\n'
+			}
+			continue
+		case isSpace(c):
+			space = true
+			continue
+		}
+
+		if prev == '\n' {
+			space = false
+		}
+		flushSpace(&out, &space, c)
+		out.WriteByte(c)
+		prev = c
+	}
+
+	return bytes.TrimSpace(out.Bytes())
+}
+
+func flushSpace(out *bytes.Buffer, space *bool, next byte) {
+	if *space && out.Len() > 0 {
+		last := out.Bytes()[out.Len()-1]
+		if last != '\n' && strings.IndexByte("{};,>", last) == -1 {
+			out.WriteByte(' ')
+		}
+	}
+	*space = false
+}
+
+// copyString copies the quoted string starting at i and returns the index of its closing quote.
+func copyString(out *bytes.Buffer, src []byte, i int, quote byte) int {
+	out.WriteByte(quote)
+	for i++; i < len(src); i++ {
+		out.WriteByte(src[i])
+		if src[i] == '\\' && i+1 < len(src) {
+			i++
+			out.WriteByte(src[i])
+			continue
+		}
+		if src[i] == quote {
+			return i
+		}
+	}
+	return i
+}
+
+// copyTemplate copies the template literal starting at i and returns the index of its closing backtick.
+// The ${} substitutions are copied as they are, strings and template literals nested in them included.
+func copyTemplate(out *bytes.Buffer, src []byte, i int) int {
+	out.WriteByte('`')
+	for i++; i < len(src); i++ {
+		c := src[i]
+		out.WriteByte(c)
+		switch {
+		case c == '\\' && i+1 < len(src):
+			i++
+			out.WriteByte(src[i])
+		case c == '`':
+			return i
+		case c == '$' && i+1 < len(src) && src[i+1] == '{':
+			i++
+			out.WriteByte('{')
+			i = copySubstitution(out, src, i+1)
+		}
+	}
+	return i
+}
+
+// copySubstitution copies the expression of a ${} starting at i and returns the index of its closing brace.
+func copySubstitution(out *bytes.Buffer, src []byte, i int) int {
+	depth := 0
+	for ; i < len(src); i++ {
+		c := src[i]
+		switch c {
+		case '"', '\'':
+			i = copyString(out, src, i, c)
+			continue
+		case '`':
+			i = copyTemplate(out, src, i)
+			continue
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				out.WriteByte(c)
+				return i
+			}
+			depth--
+		}
+		out.WriteByte(c)
+	}
+	return i
+}
+
+// regexpAllowed reports whether a / after the last significant character starts a regular expression literal.
+// A / after the postfix i++ or i-- is a division.
+func regexpAllowed(out []byte, prev byte) bool {
+	if (prev == '+' || prev == '-') && len(out) > 1 && out[len(out)-1] == prev && out[len(out)-2] == prev {
+		return false
+	}
+	if strings.IndexByte("(,=:[!&|?{};+-*%<>~^\n", prev) != -1 {
+		return true
+	}
+	if !isIdent(prev) {
+		return false
+	}
+	start := len(out)
+	for start > 0 && isIdent(out[start-1]) {
+		start--
+	}
+	return regexpKeywords[string(out[start:])]
+}
+
+// copyRegexp copies the regular expression literal starting at i, character classes included.
+func copyRegexp(out *bytes.Buffer, src []byte, i int) int {
+	out.WriteByte('/')
+	class := false
+	for i++; i < len(src); i++ {
+		c := src[i]
+		out.WriteByte(c)
+		switch {
+		case c == '\\' && i+1 < len(src):
+			i++
+			out.WriteByte(src[i])
+		case c == '[':
+			class = true
+		case c == ']':
+			class = false
+		case c == '/' && !class:
+			return i
+		case c == '\n':
+			// not a regular expression after all, e.g. a division at the start of a line
+			return i
+		}
+	}
+	return i
+}
+
+func trimTrailing(out *bytes.Buffer, c byte) {
+	if out.Len() > 0 && out.Bytes()[out.Len()-1] == c {
+		out.Truncate(out.Len() - 1)
+	}
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,52 @@
+package asset
+
+import "testing"
+
+func TestMinifyJS(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"comments", "var a = 1; // one\n/* two */ var b = 2;", "var a = 1;\nvar b = 2;"},
+		{"indentation", "function f() {\n    return 1;\n}\n\n", "function f() {\nreturn 1;\n}"},
+		{"strings", `var s = "// not a comment", t = '/* nor this */';`, `var s = "// not a comment",t = '/* nor this */';`},
+		{"division", "var x = a / b / c; // c", "var x = a / b / c;"},
+		{"division after paren", "var x = (a) / 2; // c", "var x = (a) / 2;"},
+		{"division after postfix", "i++ / 2; s = \"/\"; u = \"http://x\";\nv = 1", "i++ / 2;s = \"/\";u = \"http://x\";\nv = 1"},
+		{"division after postfix decrement", "x = i--/2; // c", "x = i--/2;"},
+		{"regexp after plus", "x = a + /b/.source; // c", "x = a + /b/.source;"},
+		{"regexp after return", `function f() { return /a\/\//.test(s); } // c`, `function f() {return /a\/\//.test(s);}`},
+		{"regexp after assignment", `var re = /\/\/[^/]*/g; // c`, `var re = /\/\/[^/]*/g;`},
+		{"regexp after typeof", "x = typeof /a//* c */", "x = typeof /a/"},
+		{"regexp class", `s.replace(/[/]/g, "");`, `s.replace(/[/]/g,"");`},
+		{"template", "var s = `a // b ${c} /* d */`;", "var s = `a // b ${c} /* d */`;"},
+		{"template nested", "var s = `a ${b ? `x${'}'}` : \"`\"} c`; // e", "var s = `a ${b ? `x${'}'}` : \"`\"} c`;"},
+		{"template object", "var s = `${ {a: 1}.a }`;", "var s = `${ {a: 1}.a }`;"},
+		{"line breaks", "var a = 1\nvar b = 2", "var a = 1\nvar b = 2"},
+	}
+
+	for _, tt := range tests {
+		if got := string(MinifyJS([]byte(tt.src))); got != tt.want {
+			t.Errorf("%s: MinifyJS(%q) = %q, want %q", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMinifyCSS(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"a { color: red; }", "a{color: red}"},
+		{"/* c */ a :hover , b > c { margin: 0 ; }", "a :hover,b>c{margin: 0}"},
+		{`a::after { content: "/* x */"; }`, `a::after{content: "/* x */"}`},
+		{"a { width: calc(1px + 2px); }", "a{width: calc(1px + 2px)}"},
+	}
+
+	for _, tt := range tests {
+		if got := string(MinifyCSS([]byte(tt.src))); got != tt.want {
+			t.Errorf("MinifyCSS(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
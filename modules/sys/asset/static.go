@@ -0,0 +1,130 @@
+package asset
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/labstack/echo/v4"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// encodings are tried in this order when the client accepts them.
+var encodings = []struct {
+	name string
+	ext  string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// Static serves the files under root from the "*" route param. A precompressed .br or .gz sibling is sent
+// when the client accepts it, conditional and range requests are handled by http.ServeContent.
+func Static(root string, cfg config.Assets) echo.HandlerFunc {
+	return StaticFS(os.DirFS(root), cfg)
+}
+
+// StaticFS is Static for a file system, e.g. the embedded files of a plugin.
+func StaticFS(fsys fs.FS, cfg config.Assets) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name, err := url.PathUnescape(c.Param("*"))
+		if err != nil {
+			return echo.ErrNotFound
+		}
+
+		name = path.Clean("/" + name)
+		if strings.Contains(name, "\x00") {
+			return echo.ErrNotFound
+		}
+
+		return serveFile(c, fsys, name[1:], cacheControl(cfg, name))
+	}
+}
+
+func serveFile(c echo.Context, fsys fs.FS, file, cache string) error {
+	if !fs.ValidPath(file) {
+		return echo.ErrNotFound
+	}
+
+	fi, err := fs.Stat(fsys, file)
+	if err != nil || !fi.Mode().IsRegular() {
+		return echo.ErrNotFound
+	}
+
+	h := c.Response().Header()
+	h.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+	served, encoding := file, ""
+	accept := c.Request().Header.Get(echo.HeaderAcceptEncoding)
+	for _, enc := range encodings {
+		if !acceptsEncoding(accept, enc.name) {
+			continue
+		}
+		if efi, err := fs.Stat(fsys, file+enc.ext); err == nil && efi.Mode().IsRegular() && !efi.ModTime().Before(fi.ModTime()) {
+			served, encoding, fi = file+enc.ext, enc.name, efi
+			break
+		}
+	}
+
+	f, err := fsys.Open(served)
+	if err != nil {
+		return echo.ErrNotFound
+	}
+	defer f.Close()
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(b)
+	}
+
+	etag := fmt.Sprintf("%x-%x", fi.ModTime().UnixNano(), fi.Size())
+	if encoding != "" {
+		etag += "-" + encoding
+		h.Set(echo.HeaderContentEncoding, encoding)
+	}
+	h.Set("ETag", `"`+etag+`"`)
+	h.Set("Cache-Control", cache)
+
+	// the name is used for the content type, which is the one of the original file
+	http.ServeContent(c.Response(), c.Request(), path.Base(file), fi.ModTime(), content)
+	return nil
+}
+
+func cacheControl(cfg config.Assets, name string) string {
+	maxAge, ok := cfg.MaxAge[strings.TrimPrefix(strings.ToLower(path.Ext(name)), ".")]
+	if !ok {
+		maxAge = cfg.MaxAge["*"]
+	}
+	if maxAge <= 0 {
+		return "no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second))
+}
+
+// acceptsEncoding reads Accept-Encoding, an encoding with q=0 is refused.
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), encoding) {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == "q=0" || param == "q=0.0" || param == "q=0.00" || param == "q=0.000" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
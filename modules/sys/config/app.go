@@ -4,6 +4,10 @@ type App struct {
 	Debug    bool   `env:"DEBUG" env-default:"false" yaml:"debug" json:"debug"`
 	Name     string `env:"NAME" env-default:"Spring CMS" yaml:"name" json:"name"`
 	Port     int    `env:"PORT" env-default:"80" yaml:"port" json:"port"`
+	URL      string `env:"URL" env-default:"http://localhost" yaml:"url" json:"url"`
 	Timezone string `env:"TIMEZONE" env-default:"UTC" yaml:"timezone" json:"timezone"`
 	Locale   string `env:"LOCALE" env-default:"en" yaml:"locale" json:"locale"`
+	// TrustedProxies are the IPs or CIDRs of the reverse proxies whose X-Forwarded-For is used for the client IP,
+	// without them the IP of the connection is used.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" yaml:"trusted_proxies" json:"trusted_proxies"`
 }
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type Assets struct {
+	CachePath string `env-default:"./storage/assets" env:"CACHE_PATH" yaml:"cache_path" json:"cache_path"`
+	Minify    bool   `env-default:"true" env:"MINIFY" yaml:"minify" json:"minify"`
+	// MaxAge is the Cache-Control max-age of static files by extension, "*" is used for the others.
+	MaxAge map[string]time.Duration `env-default:"css:168h,js:168h,*:1h" env:"MAX_AGE" yaml:"max_age" json:"max_age"`
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+type (
+	Throttle struct {
+		MaxAttempts   int           `env-default:"5" env:"MAX_ATTEMPTS" yaml:"max_attempts" json:"max_attempts"`
+		IPMaxAttempts int           `env-default:"20" env:"IP_MAX_ATTEMPTS" yaml:"ip_max_attempts" json:"ip_max_attempts"`
+		Window        time.Duration `env-default:"15m" env:"WINDOW" yaml:"window" json:"window"`
+		Lockout       time.Duration `env-default:"15m" env:"LOCKOUT" yaml:"lockout" json:"lockout"`
+		BaseDelay     time.Duration `env-default:"1s" env:"BASE_DELAY" yaml:"base_delay" json:"base_delay"`
+		MaxDelay      time.Duration `env-default:"1m" env:"MAX_DELAY" yaml:"max_delay" json:"max_delay"`
+	}
+
+	Auth struct {
+		Throttle         Throttle      `env-prefix:"THROTTLE_" yaml:"throttle" json:"throttle"`
+		PasswordResetTTL time.Duration `env-default:"1h" env:"PASSWORD_RESET_TTL" yaml:"password_reset_ttl" json:"password_reset_ttl"`
+		EmailVerifyTTL   time.Duration `env-default:"48h" env:"EMAIL_VERIFY_TTL" yaml:"email_verify_ttl" json:"email_verify_ttl"`
+		// ForgotIPMaxAttempts is the number of forgot password requests from one IP per ForgotWindow.
+		ForgotIPMaxAttempts int           `env-default:"10" env:"FORGOT_IP_MAX_ATTEMPTS" yaml:"forgot_ip_max_attempts" json:"forgot_ip_max_attempts"`
+		ForgotWindow        time.Duration `env-default:"1h" env:"FORGOT_WINDOW" yaml:"forgot_window" json:"forgot_window"`
+		// MailCooldown is the time before another reset or verification email is sent to the same user.
+		MailCooldown time.Duration `env-default:"2m" env:"MAIL_COOLDOWN" yaml:"mail_cooldown" json:"mail_cooldown"`
+	}
+)
@@ -1,10 +1,16 @@
 package config
 
 type Cfg struct {
-	App   App   `env-prefix:"APP_" yaml:"app" json:"app"`
-	CMS   CMS   `env-prefix:"CMS_" yaml:"cms" json:"cms"`
-	CORS  CORS  `env-prefix:"CORS_" yaml:"cors" json:"cors"`
-	JWT   JWT   `env-prefix:"JWT_" yaml:"jwt" json:"jwt"`
-	DB    DB    `env-prefix:"DB_" yaml:"db" json:"db"`
-	Redis Redis `env-prefix:"REDIS_" yaml:"redis" json:"redis"`
+	App      App      `env-prefix:"APP_" yaml:"app" json:"app"`
+	Assets   Assets   `env-prefix:"ASSETS_" yaml:"assets" json:"assets"`
+	Auth     Auth     `env-prefix:"AUTH_" yaml:"auth" json:"auth"`
+	CMS      CMS      `env-prefix:"CMS_" yaml:"cms" json:"cms"`
+	CORS     CORS     `env-prefix:"CORS_" yaml:"cors" json:"cors"`
+	JWT      JWT      `env-prefix:"JWT_" yaml:"jwt" json:"jwt"`
+	DB       DB       `env-prefix:"DB_" yaml:"db" json:"db"`
+	Mail     Mail     `env-prefix:"MAIL_" yaml:"mail" json:"mail"`
+	Media    Media    `env-prefix:"MEDIA_" yaml:"media" json:"media"`
+	Queue    Queue    `env-prefix:"QUEUE_" yaml:"queue" json:"queue"`
+	Redis    Redis    `env-prefix:"REDIS_" yaml:"redis" json:"redis"`
+	Schedule Schedule `env-prefix:"SCHEDULE_" yaml:"schedule" json:"schedule"`
 }
@@ -0,0 +1,17 @@
+package config
+
+type Mail struct {
+	Driver     string `env-default:"log" env:"DRIVER" yaml:"driver" json:"driver"`
+	FromEmail  string `env-default:"noreply@localhost" env:"FROM_EMAIL" yaml:"from_email" json:"from_email"`
+	FromName   string `env-default:"Spring CMS" env:"FROM_NAME" yaml:"from_name" json:"from_name"`
+	Host       string `env-default:"localhost" env:"HOST" yaml:"host" json:"host"`
+	Port       int    `env-default:"25" env:"PORT" yaml:"port" json:"port"`
+	Username   string `env-default:"" env:"USERNAME" yaml:"username" json:"username"`
+	Password   string `env-default:"" env:"PASSWORD" yaml:"password" json:"password"`
+	Encryption string `env-default:"" env:"ENCRYPTION" yaml:"encryption" json:"encryption"` // "", "tls" or "starttls"
+	Path       string `env-default:"./storage/mail" env:"PATH" yaml:"path" json:"path"`
+
+	// Queue sends the messages as jobs of the job queue, the backoff between the retries is queue.backoff.
+	Queue        bool `env-default:"false" env:"QUEUE" yaml:"queue" json:"queue"`
+	QueueRetries int  `env-default:"3" env:"QUEUE_RETRIES" yaml:"queue_retries" json:"queue_retries"`
+}
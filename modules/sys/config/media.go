@@ -0,0 +1,25 @@
+package config
+
+type (
+	Media struct {
+		Driver string `env-default:"local" env:"DRIVER" yaml:"driver" json:"driver"` // "local" or "s3"
+		Path   string `env-default:"./storage/app/media" env:"PATH" yaml:"path" json:"path"`
+		// URL is the public prefix of the files, for s3 the URL of the bucket, e.g. "http://localhost:9000/media".
+		URL     string `env-default:"/storage/media" env:"URL" yaml:"url" json:"url"`
+		MaxSize int64  `env-default:"10485760" env:"MAX_SIZE" yaml:"max_size" json:"max_size"`
+		// svg is left out on purpose, an SVG served from the site origin can run scripts.
+		Extensions []string `env-default:"jpg,jpeg,png,gif,webp,pdf,txt,zip,mp3,mp4" env:"EXTENSIONS" yaml:"extensions" json:"extensions"`
+		ThumbsDir  string   `env-default:"_thumbs" env:"THUMBS_DIR" yaml:"thumbs_dir" json:"thumbs_dir"`
+		S3         MediaS3  `env-prefix:"S3_" yaml:"s3" json:"s3"`
+	}
+
+	MediaS3 struct {
+		Endpoint  string `env-default:"http://localhost:9000" env:"ENDPOINT" yaml:"endpoint" json:"endpoint"`
+		Region    string `env-default:"us-east-1" env:"REGION" yaml:"region" json:"region"`
+		Bucket    string `env-default:"media" env:"BUCKET" yaml:"bucket" json:"bucket"`
+		AccessKey string `env-default:"" env:"ACCESS_KEY" yaml:"access_key" json:"-"`
+		SecretKey string `env-default:"" env:"SECRET_KEY" yaml:"secret_key" json:"-"`
+		// PathStyle puts the bucket in the path instead of the host name, MinIO needs it.
+		PathStyle bool `env-default:"true" env:"PATH_STYLE" yaml:"path_style" json:"path_style"`
+	}
+)
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type Queue struct {
+	Name            string        `env-default:"default" env:"NAME" yaml:"name" json:"name"`
+	Concurrency     int           `env-default:"4" env:"CONCURRENCY" yaml:"concurrency" json:"concurrency"`
+	MaxAttempts     int           `env-default:"3" env:"MAX_ATTEMPTS" yaml:"max_attempts" json:"max_attempts"`
+	Backoff         time.Duration `env-default:"10s" env:"BACKOFF" yaml:"backoff" json:"backoff"`
+	MaxBackoff      time.Duration `env-default:"1h" env:"MAX_BACKOFF" yaml:"max_backoff" json:"max_backoff"`
+	Timeout         time.Duration `env-default:"5m" env:"TIMEOUT" yaml:"timeout" json:"timeout"`
+	ShutdownTimeout time.Duration `env-default:"30s" env:"SHUTDOWN_TIMEOUT" yaml:"shutdown_timeout" json:"shutdown_timeout"`
+}
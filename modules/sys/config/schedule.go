@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type Schedule struct {
+	Web             bool          `env-default:"false" env:"WEB" yaml:"web" json:"web"`
+	Timeout         time.Duration `env-default:"1h" env:"TIMEOUT" yaml:"timeout" json:"timeout"`
+	LockTTL         time.Duration `env-default:"1h" env:"LOCK_TTL" yaml:"lock_ttl" json:"lock_ttl"`
+	ShutdownTimeout time.Duration `env-default:"30s" env:"SHUTDOWN_TIMEOUT" yaml:"shutdown_timeout" json:"shutdown_timeout"`
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/iagapie/go-spring/modules/sys/view"
 	"github.com/labstack/echo/v4"
 	"html/template"
+	"io/fs"
 	"path/filepath"
 	"sync"
 )
@@ -13,6 +14,7 @@ type (
 	Datasource interface {
 		Funcs(funcMap template.FuncMap)
 		SelectOne(dir, name, ext string) view.View
+		SelectOneFS(fsys fs.FS, dir, name, ext string) view.View
 		Select(dir, ext string) ViewMap
 	}
 
@@ -52,6 +54,23 @@ func (ds *fileDatasource) SelectOne(dir, name, ext string) view.View {
 	return v
 }
 
+// SelectOneFS is SelectOne reading from fsys, e.g. the files embedded in a static plugin.
+func (ds *fileDatasource) SelectOneFS(fsys fs.FS, dir, name, ext string) view.View {
+	file := fmt.Sprintf("%s/%s.%s", dir, name, ext)
+	b, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil
+	}
+	ds.mu.RLock()
+	v := view.New(file, view.WithFuncs(ds.funcs), view.WithContent(string(b)))
+	ds.mu.RUnlock()
+	if err = v.Load(); err != nil {
+		ds.log.Warn(err)
+		return nil
+	}
+	return v
+}
+
 func (ds *fileDatasource) Select(dir, ext string) ViewMap {
 	views := make(ViewMap)
 
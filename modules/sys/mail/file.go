@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFile creates a mailer that stores every message as an .eml file in dir.
+func NewFile(dir string) Mailer {
+	return &fileMailer{dir: dir}
+}
+
+func (m *fileMailer) Send(_ context.Context, msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("mail file: %w", err)
+	}
+	if err = os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("mail file: %w", err)
+	}
+	file := filepath.Join(m.dir, fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), randomID()[:8]))
+	if err = os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("mail file: %w", err)
+	}
+	return nil
+}
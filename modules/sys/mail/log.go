@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+)
+
+type logMailer struct {
+	log echo.Logger
+}
+
+// NewLog creates a mailer that only writes messages to the log, useful for local development.
+func NewLog(log echo.Logger) Mailer {
+	return &logMailer{log: log}
+}
+
+func (m *logMailer) Send(_ context.Context, msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	m.log.Infof("mail log: to %s, subject %q\n%s", joinAddresses(msg.To), msg.Subject, data)
+	return nil
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/labstack/echo/v4"
+	"net/mail"
+	"os"
+	"path/filepath"
+)
+
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+	DriverSMTP = "smtp"
+)
+
+var ErrNoRecipients = errors.New("mail: message has no recipients")
+
+type (
+	Message struct {
+		From        mail.Address   `json:"from"`
+		ReplyTo     []mail.Address `json:"reply_to,omitempty"`
+		To          []mail.Address `json:"to"`
+		Cc          []mail.Address `json:"cc,omitempty"`
+		Bcc         []mail.Address `json:"bcc,omitempty"`
+		Subject     string         `json:"subject"`
+		HTML        string         `json:"html,omitempty"`
+		Text        string         `json:"text,omitempty"`
+		Attachments []Attachment   `json:"attachments,omitempty"`
+	}
+
+	Attachment struct {
+		Filename    string `json:"filename"`
+		ContentType string `json:"content_type,omitempty"`
+		Data        []byte `json:"data"`
+	}
+
+	Mailer interface {
+		Send(ctx context.Context, msg Message) error
+	}
+)
+
+// New creates the mailer configured by cfg.Driver. Messages without a sender get cfg.FromEmail and cfg.FromName.
+func New(cfg config.Mail, log echo.Logger) (Mailer, error) {
+	var m Mailer
+	switch cfg.Driver {
+	case DriverLog, "":
+		m = NewLog(log)
+	case DriverFile:
+		m = NewFile(cfg.Path)
+	case DriverSMTP:
+		m = NewSMTP(cfg)
+	default:
+		return nil, fmt.Errorf("mail: unknown driver %s", cfg.Driver)
+	}
+	return &defaults{
+		Mailer: m,
+		from:   mail.Address{Name: cfg.FromName, Address: cfg.FromEmail},
+	}, nil
+}
+
+func NewMessage(subject string, to ...mail.Address) Message {
+	return Message{
+		To:      to,
+		Subject: subject,
+	}
+}
+
+// Attach adds a file attachment. The content type is guessed from the file name when it is empty.
+func (m *Message) Attach(filename, contentType string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	})
+}
+
+// AttachFile reads the file from disk and adds it as an attachment.
+func (m *Message) AttachFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("mail: attach: %w", err)
+	}
+	m.Attach(filepath.Base(file), "", data)
+	return nil
+}
+
+type defaults struct {
+	Mailer
+	from mail.Address
+}
+
+func (d *defaults) Send(ctx context.Context, msg Message) error {
+	if msg.From.Address == "" {
+		msg.From = d.from
+	}
+	if len(msg.Recipients()) == 0 {
+		return ErrNoRecipients
+	}
+	return d.Mailer.Send(ctx, msg)
+}
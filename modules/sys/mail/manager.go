@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+	"net/mail"
+)
+
+// Manager is the entry point used by the CMS, plugins and components to send mail.
+type Manager struct {
+	Mailer
+	Templates *Templates
+}
+
+func NewManager(mailer Mailer, templates *Templates) *Manager {
+	return &Manager{
+		Mailer:    mailer,
+		Templates: templates,
+	}
+}
+
+// Compose renders the template into a new message addressed to the recipients.
+func (m *Manager) Compose(template string, vars interface{}, to ...mail.Address) (Message, error) {
+	content, err := m.Templates.Render(template, vars)
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := NewMessage(content.Subject, to...)
+	msg.HTML = content.HTML
+	msg.Text = content.Text
+	return msg, nil
+}
+
+func (m *Manager) SendTemplate(ctx context.Context, template string, vars interface{}, to ...mail.Address) error {
+	msg, err := m.Compose(template, vars, to...)
+	if err != nil {
+		return err
+	}
+	return m.Send(ctx, msg)
+}
@@ -0,0 +1,161 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const lineLength = 76
+
+// Bytes renders the message as an RFC 5322 document ready to be sent or stored.
+// Bcc recipients are left out of the headers.
+func (m Message) Bytes() ([]byte, error) {
+	b := new(bytes.Buffer)
+
+	writeHeader(b, "From", m.From.String())
+	if len(m.ReplyTo) > 0 {
+		writeHeader(b, "Reply-To", joinAddresses(m.ReplyTo))
+	}
+	writeHeader(b, "To", joinAddresses(m.To))
+	if len(m.Cc) > 0 {
+		writeHeader(b, "Cc", joinAddresses(m.Cc))
+	}
+	writeHeader(b, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(b, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(b, "Message-ID", fmt.Sprintf("<%s@%s>", randomID(), domain(m.From.Address)))
+	writeHeader(b, "MIME-Version", "1.0")
+
+	if len(m.Attachments) == 0 {
+		if err := m.writeContent(b); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+
+	boundary := randomID()
+	writeHeader(b, "Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", boundary))
+	b.WriteString("\r\n")
+
+	b.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	if err := m.writeContent(b); err != nil {
+		return nil, err
+	}
+
+	for _, a := range m.Attachments {
+		b.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		writeAttachment(b, a)
+	}
+	b.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+
+	return b.Bytes(), nil
+}
+
+// Recipients returns the envelope recipients of the message: To, Cc and Bcc.
+func (m Message) Recipients() []string {
+	rcpt := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	for _, list := range [][]mail.Address{m.To, m.Cc, m.Bcc} {
+		for _, a := range list {
+			rcpt = append(rcpt, a.Address)
+		}
+	}
+	return rcpt
+}
+
+func (m Message) writeContent(b *bytes.Buffer) error {
+	switch {
+	case m.HTML != "" && m.Text != "":
+		boundary := randomID()
+		writeHeader(b, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+		b.WriteString("\r\n")
+		if err := writePart(b, boundary, "text/plain", m.Text); err != nil {
+			return err
+		}
+		if err := writePart(b, boundary, "text/html", m.HTML); err != nil {
+			return err
+		}
+		b.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+		return nil
+	case m.HTML != "":
+		return writeBody(b, "text/html", m.HTML)
+	default:
+		return writeBody(b, "text/plain", m.Text)
+	}
+}
+
+func writeHeader(b *bytes.Buffer, name, value string) {
+	b.WriteString(fmt.Sprintf("%s: %s\r\n", name, value))
+}
+
+func writePart(b *bytes.Buffer, boundary, contentType, body string) error {
+	b.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	return writeBody(b, contentType, body)
+}
+
+func writeBody(b *bytes.Buffer, contentType, body string) error {
+	writeHeader(b, "Content-Type", fmt.Sprintf("%s; charset=utf-8", contentType))
+	writeHeader(b, "Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+	w := quotedprintable.NewWriter(b)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	b.WriteString("\r\n")
+	return nil
+}
+
+func writeAttachment(b *bytes.Buffer, a Attachment) {
+	contentType := a.ContentType
+	if contentType == "" {
+		if contentType = mime.TypeByExtension(filepath.Ext(a.Filename)); contentType == "" {
+			contentType = "application/octet-stream"
+		}
+	}
+	filename := mime.QEncoding.Encode("utf-8", a.Filename)
+
+	writeHeader(b, "Content-Type", fmt.Sprintf("%s; name=%q", contentType, filename))
+	writeHeader(b, "Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	writeHeader(b, "Content-Transfer-Encoding", "base64")
+	b.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > lineLength {
+		b.WriteString(encoded[:lineLength])
+		b.WriteString("\r\n")
+		encoded = encoded[lineLength:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+}
+
+func joinAddresses(addresses []mail.Address) string {
+	list := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		list = append(list, a.String())
+	}
+	return strings.Join(list, ", ")
+}
+
+func domain(address string) string {
+	if index := strings.LastIndex(address, "@"); index != -1 {
+		return address[index+1:]
+	}
+	return "localhost"
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/queue"
+)
+
+// JobSend is the job type of the queued messages.
+const JobSend = "mail:send"
+
+// Queue is a Mailer that dispatches every message as a job of the job queue,
+// queue:work sends them with the wrapped mailer.
+type Queue struct {
+	queue       *queue.Queue
+	mailer      Mailer
+	maxAttempts int
+}
+
+// NewQueue registers the JobSend handler, so the queue of every process which works the jobs needs it.
+func NewQueue(cfg config.Mail, q *queue.Queue, mailer Mailer) *Queue {
+	mq := &Queue{
+		queue:       q,
+		mailer:      mailer,
+		maxAttempts: cfg.QueueRetries + 1,
+	}
+	q.Register(JobSend, mq.handle)
+	return mq
+}
+
+func (q *Queue) Send(ctx context.Context, msg Message) error {
+	if len(msg.Recipients()) == 0 {
+		return ErrNoRecipients
+	}
+	if _, err := q.queue.Dispatch(ctx, JobSend, msg, queue.WithMaxAttempts(q.maxAttempts)); err != nil {
+		return fmt.Errorf("mail queue: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) handle(ctx context.Context, job *queue.Job) error {
+	var msg Message
+	if err := job.Bind(&msg); err != nil {
+		return fmt.Errorf("mail queue: decode: %w", err)
+	}
+	return q.mailer.Send(ctx, msg)
+}
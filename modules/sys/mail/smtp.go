@@ -0,0 +1,84 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+type smtpMailer struct {
+	cfg config.Mail
+}
+
+func NewSMTP(cfg config.Mail) Mailer {
+	return &smtpMailer{cfg: cfg}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("mail smtp: %w", err)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsCfg := &tls.Config{ServerName: m.cfg.Host}
+
+	dialer := new(net.Dialer)
+	var conn net.Conn
+	if strings.EqualFold(m.cfg.Encryption, "tls") {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsCfg}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("mail smtp: dial: %w", err)
+	}
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mail smtp: %w", err)
+	}
+	defer c.Close()
+
+	if strings.EqualFold(m.cfg.Encryption, "starttls") {
+		if err = c.StartTLS(tlsCfg); err != nil {
+			return fmt.Errorf("mail smtp: starttls: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err = c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+				return fmt.Errorf("mail smtp: auth: %w", err)
+			}
+		}
+	}
+
+	if err = c.Mail(msg.From.Address); err != nil {
+		return fmt.Errorf("mail smtp: %w", err)
+	}
+	for _, rcpt := range msg.Recipients() {
+		if err = c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("mail smtp: rcpt %s: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mail smtp: %w", err)
+	}
+	if _, err = w.Write(data); err != nil {
+		return fmt.Errorf("mail smtp: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("mail smtp: %w", err)
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/view"
+	"io/fs"
+	"sync"
+)
+
+const (
+	extHTML = "html"
+	extText = "txt"
+)
+
+type (
+	// Content is a rendered mail template. Subject comes from the "subject" property in the template [cfg] section.
+	Content struct {
+		Subject string
+		HTML    string
+		Text    string
+	}
+
+	// Templates renders mail templates. For a template "name" it looks for name.html and name.txt in every
+	// directory returned by dirs (e.g. the active theme "mail" directory) before the registered fallbacks.
+	Templates struct {
+		mu        sync.RWMutex
+		dirs      func() []string
+		fallbacks []fs.FS
+	}
+)
+
+func NewTemplates(dirs func() []string, fallbacks ...fs.FS) *Templates {
+	return &Templates{
+		dirs:      dirs,
+		fallbacks: fallbacks,
+	}
+}
+
+// AddFallback registers built-in templates, e.g. the ones a plugin ships with.
+func (t *Templates) AddFallback(fallback fs.FS) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.fallbacks = append(t.fallbacks, fallback)
+}
+
+func (t *Templates) Render(name string, vars interface{}) (Content, error) {
+	var content Content
+
+	htmlView := t.find(name, extHTML)
+	textView := t.find(name, extText, view.WithText())
+	if htmlView == nil && textView == nil {
+		return content, fmt.Errorf("mail template %s not found", name)
+	}
+
+	for _, item := range []struct {
+		v   view.View
+		out *string
+	}{{htmlView, &content.HTML}, {textView, &content.Text}} {
+		if item.v == nil {
+			continue
+		}
+		result, err := item.v.Render(vars)
+		if err != nil {
+			return content, fmt.Errorf("mail template %s: %w", item.v.File(), err)
+		}
+		*item.out = result
+		if subject := item.v.Prop("subject"); content.Subject == "" {
+			content.Subject = subject
+		}
+	}
+
+	return content, nil
+}
+
+func (t *Templates) find(name, ext string, opts ...view.Option) view.View {
+	file := fmt.Sprintf("%s.%s", name, ext)
+
+	if t.dirs != nil {
+		for _, dir := range t.dirs() {
+			if path := fmt.Sprintf("%s/%s", dir, file); helper.FileExists(path) {
+				return view.New(path, opts...)
+			}
+		}
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, fallback := range t.fallbacks {
+		if b, err := fs.ReadFile(fallback, file); err == nil {
+			return view.New(file, append(opts, view.WithContent(string(b)))...)
+		}
+	}
+
+	return nil
+}
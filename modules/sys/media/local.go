@@ -0,0 +1,134 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type localStorage struct {
+	root string
+	url  string
+}
+
+// NewLocal creates a storage keeping the files under root, they are served at url by the web command.
+func NewLocal(root, url string) Storage {
+	return &localStorage{root: root, url: url}
+}
+
+func (s *localStorage) Put(_ context.Context, p string, r io.Reader, _ int64, _ string) error {
+	file := s.file(p)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("media local: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+"-")
+	if err != nil {
+		return fmt.Errorf("media local: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("media local: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("media local: %w", err)
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("media local: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("media local: %w", err)
+	}
+	return nil
+}
+
+func (s *localStorage) Get(_ context.Context, p string) (io.ReadCloser, error) {
+	f, err := os.Open(s.file(p))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("media local: %w", err)
+	}
+	return f, nil
+}
+
+func (s *localStorage) Stat(_ context.Context, p string) (File, error) {
+	fi, err := os.Stat(s.file(p))
+	if errors.Is(err, fs.ErrNotExist) {
+		return File{}, fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("media local: %w", err)
+	}
+	return s.toFile(p, fi), nil
+}
+
+// Delete removes a file or an empty directory.
+func (s *localStorage) Delete(_ context.Context, p string) error {
+	err := os.Remove(s.file(p))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+	if err != nil {
+		return fmt.Errorf("media local: %w", err)
+	}
+	return nil
+}
+
+func (s *localStorage) List(_ context.Context, dir string) ([]File, error) {
+	entries, err := os.ReadDir(s.file(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("media local: %w", err)
+	}
+
+	files := make([]File, 0, len(entries))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, s.toFile(path.Join(dir, e.Name()), fi))
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+func (s *localStorage) URL(p string) string {
+	return joinURL(s.url, p)
+}
+
+func (s *localStorage) file(p string) string {
+	return filepath.Join(s.root, filepath.FromSlash(p))
+}
+
+func (s *localStorage) toFile(p string, fi fs.FileInfo) File {
+	f := File{
+		Path:    p,
+		Name:    path.Base(p),
+		ModTime: fi.ModTime(),
+		IsDir:   fi.IsDir(),
+	}
+	if !f.IsDir {
+		f.Size = fi.Size()
+		f.URL = s.URL(p)
+	}
+	return f
+}
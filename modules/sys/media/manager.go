@@ -0,0 +1,301 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/labstack/echo/v4"
+	"io"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const maxDimension = 8192
+
+var (
+	ErrTooLarge  = errors.New("media: file is too large")
+	ErrExtension = errors.New("media: file type is not allowed")
+
+	nameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+type (
+	Manager struct {
+		Storage
+		cfg    config.Media
+		log    echo.Logger
+		mu     sync.RWMutex
+		thumbs map[string]string
+		calls  map[string]*call
+	}
+
+	// call is a thumbnail being generated, the requests for the same thumbnail wait for it.
+	call struct {
+		wg  sync.WaitGroup
+		err error
+	}
+)
+
+func NewManager(storage Storage, cfg config.Media, log echo.Logger) *Manager {
+	return &Manager{
+		Storage: storage,
+		cfg:     cfg,
+		log:     log,
+		thumbs:  make(map[string]string),
+		calls:   make(map[string]*call),
+	}
+}
+
+// Upload stores the file in dir. The name is sanitized and, when taken, gets a -1, -2, ... suffix.
+func (m *Manager) Upload(ctx context.Context, dir, name string, r io.Reader, size int64) (File, error) {
+	dir, err := m.userPath(dir)
+	if err != nil {
+		return File{}, err
+	}
+
+	name = path.Base(name)
+	ext := strings.ToLower(nameRegex.ReplaceAllString(path.Ext(name), ""))
+	name = strings.Trim(nameRegex.ReplaceAllString(strings.TrimSuffix(name, path.Ext(name)), "-"), "-.") + ext
+	if name == ext || !m.allowed(ext) {
+		return File{}, fmt.Errorf("%w: %s", ErrExtension, name)
+	}
+	if size > m.cfg.MaxSize {
+		return File{}, fmt.Errorf("%w: %d bytes, the limit is %d", ErrTooLarge, size, m.cfg.MaxSize)
+	}
+
+	p, err := m.unique(ctx, path.Join(dir, name))
+	if err != nil {
+		return File{}, err
+	}
+
+	// the declared size can lie, read one byte over the limit to notice
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, io.LimitReader(r, m.cfg.MaxSize+1)); err != nil {
+		return File{}, fmt.Errorf("media: %w", err)
+	}
+	if int64(buf.Len()) > m.cfg.MaxSize {
+		return File{}, fmt.Errorf("%w: the limit is %d bytes", ErrTooLarge, m.cfg.MaxSize)
+	}
+
+	if err = m.Put(ctx, p, &buf, int64(buf.Len()), mime.TypeByExtension(ext)); err != nil {
+		return File{}, err
+	}
+	m.forget(p)
+	return m.Stat(ctx, p)
+}
+
+// List hides the thumbnails directory.
+func (m *Manager) List(ctx context.Context, dir string) ([]File, error) {
+	dir, err := m.userPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := m.Storage.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := files[:0]
+	for _, f := range files {
+		if f.Path != m.cfg.ThumbsDir {
+			visible = append(visible, f)
+		}
+	}
+	return visible, nil
+}
+
+// Delete removes the file and its thumbnails.
+func (m *Manager) Delete(ctx context.Context, p string) error {
+	p, err := m.userPath(p)
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		return fmt.Errorf("%w: the root can not be deleted", ErrInvalidPath)
+	}
+	if err = m.Storage.Delete(ctx, p); err != nil {
+		return err
+	}
+	m.forget(p)
+
+	thumbs, err := m.Storage.List(ctx, path.Join(m.cfg.ThumbsDir, path.Dir(p)))
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			m.log.Warnf("media: thumbnails of %s: %v", p, err)
+		}
+		return nil
+	}
+	thumbRegex := regexp.MustCompile(`^` + regexp.QuoteMeta(strings.TrimSuffix(path.Base(p), path.Ext(p))) +
+		`_\d+x\d+_(` + ModeFit + `|` + ModeCrop + `|` + ModeExact + `)` + regexp.QuoteMeta(path.Ext(p)) + `$`)
+	for _, t := range thumbs {
+		if !t.IsDir && thumbRegex.MatchString(t.Name) {
+			if err = m.Storage.Delete(ctx, t.Path); err != nil {
+				m.log.Warnf("media: thumbnail %s: %v", t.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// URL is the media template func, {{ media "images/logo.png" }}.
+func (m *Manager) URL(p string) string {
+	if cleaned, err := CleanPath(p); err == nil {
+		p = cleaned
+	}
+	return m.Storage.URL(p)
+}
+
+// ResizeURL is the resize template func, {{ resize "images/photo.jpg" 300 200 "crop" }}. The mode is fit by default.
+func (m *Manager) ResizeURL(p string, width, height int, mode ...string) (string, error) {
+	md := ModeFit
+	if len(mode) > 0 && mode[0] != "" {
+		md = mode[0]
+	}
+	return m.Resize(context.Background(), p, width, height, md)
+}
+
+// Resize returns the URL of the thumbnail, generating it when it is missing or older than the image.
+// Thumbnails are stored as <thumbs_dir>/<dir>/<name>_<width>x<height>_<mode>.<ext>.
+func (m *Manager) Resize(ctx context.Context, p string, width, height int, mode string) (string, error) {
+	p, err := m.userPath(p)
+	if err != nil {
+		return "", err
+	}
+	if mode != ModeFit && mode != ModeCrop && mode != ModeExact {
+		return "", fmt.Errorf("media: resize mode must be %s, %s or %s, got %q", ModeFit, ModeCrop, ModeExact, mode)
+	}
+	if width < 0 || height < 0 || width > maxDimension || height > maxDimension || width+height == 0 {
+		return "", fmt.Errorf("media: invalid resize %dx%d", width, height)
+	}
+
+	ext := path.Ext(p)
+	thumb := path.Join(m.cfg.ThumbsDir, path.Dir(p), fmt.Sprintf("%s_%dx%d_%s%s", strings.TrimSuffix(path.Base(p), ext), width, height, mode, ext))
+
+	m.mu.RLock()
+	uri, ok := m.thumbs[thumb]
+	m.mu.RUnlock()
+	if ok {
+		return uri, nil
+	}
+
+	err = m.once(thumb, func() error {
+		orig, err := m.Stat(ctx, p)
+		if err != nil {
+			return err
+		}
+		if t, err := m.Stat(ctx, thumb); err != nil || t.ModTime.Before(orig.ModTime) {
+			return m.generate(ctx, p, thumb, width, height, mode)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	uri = m.Storage.URL(thumb)
+	m.mu.Lock()
+	m.thumbs[thumb] = uri
+	m.mu.Unlock()
+	return uri, nil
+}
+
+func (m *Manager) generate(ctx context.Context, p, thumb string, width, height int, mode string) error {
+	rc, err := m.Get(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	if err = Resize(&buf, rc, width, height, mode); err != nil {
+		return fmt.Errorf("media: resize %s: %w", p, err)
+	}
+	if err = m.Put(ctx, thumb, &buf, int64(buf.Len()), mime.TypeByExtension(strings.ToLower(path.Ext(p)))); err != nil {
+		return err
+	}
+	m.log.Debugf("media: %s was generated", thumb)
+	return nil
+}
+
+// once runs fn for the thumbnail unless it is already running, then it waits for that run and returns its error.
+func (m *Manager) once(thumb string, fn func() error) error {
+	m.mu.Lock()
+	if c, ok := m.calls[thumb]; ok {
+		m.mu.Unlock()
+		c.wg.Wait()
+		return c.err
+	}
+	c := new(call)
+	c.wg.Add(1)
+	m.calls[thumb] = c
+	m.mu.Unlock()
+
+	c.err = fn()
+
+	m.mu.Lock()
+	delete(m.calls, thumb)
+	m.mu.Unlock()
+	c.wg.Done()
+
+	return c.err
+}
+
+// forget drops the cached thumbnail URLs of p so they are checked again.
+func (m *Manager) forget(p string) {
+	ext := path.Ext(p)
+	prefix := path.Join(m.cfg.ThumbsDir, strings.TrimSuffix(p, ext)) + "_"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for thumb := range m.thumbs {
+		if strings.HasPrefix(thumb, prefix) && path.Ext(thumb) == ext {
+			delete(m.thumbs, thumb)
+		}
+	}
+}
+
+// userPath cleans a path given by a user or a template, the thumbnails directory is off limits.
+func (m *Manager) userPath(p string) (string, error) {
+	p, err := CleanPath(p)
+	if err != nil {
+		return "", err
+	}
+	if p == m.cfg.ThumbsDir || strings.HasPrefix(p, m.cfg.ThumbsDir+"/") {
+		return "", fmt.Errorf("%w: %s is reserved for thumbnails", ErrInvalidPath, m.cfg.ThumbsDir)
+	}
+	return p, nil
+}
+
+func (m *Manager) unique(ctx context.Context, p string) (string, error) {
+	ext := path.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	for i := 0; i < 1000; i++ {
+		candidate := p
+		if i > 0 {
+			candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		_, err := m.Stat(ctx, candidate)
+		if errors.Is(err, ErrNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("media: no free name for %s", p)
+}
+
+func (m *Manager) allowed(ext string) bool {
+	ext = strings.TrimPrefix(ext, ".")
+	for _, e := range m.cfg.Extensions {
+		if strings.EqualFold(strings.TrimPrefix(e, "."), ext) {
+			return ext != ""
+		}
+	}
+	return false
+}
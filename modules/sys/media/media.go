@@ -0,0 +1,78 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound    = errors.New("media: file not found")
+	ErrInvalidPath = errors.New("media: invalid path")
+)
+
+type (
+	File struct {
+		Path    string    `json:"path"`
+		Name    string    `json:"name"`
+		Size    int64     `json:"size"`
+		ModTime time.Time `json:"mod_time"`
+		IsDir   bool      `json:"is_dir"`
+		URL     string    `json:"url,omitempty"`
+	}
+
+	// Storage keeps the media files. Paths are slash separated and relative to the root of the storage.
+	Storage interface {
+		Put(ctx context.Context, p string, r io.Reader, size int64, contentType string) error
+		Get(ctx context.Context, p string) (io.ReadCloser, error)
+		Stat(ctx context.Context, p string) (File, error)
+		Delete(ctx context.Context, p string) error
+		// List returns the files and the directories directly under dir.
+		List(ctx context.Context, dir string) ([]File, error)
+		URL(p string) string
+	}
+)
+
+// New creates the storage configured by cfg.Driver.
+func New(cfg config.Media) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal, "":
+		return NewLocal(cfg.Path, cfg.URL), nil
+	case DriverS3:
+		return NewS3(cfg.S3, cfg.URL)
+	}
+	return nil, fmt.Errorf("media: unknown driver %s", cfg.Driver)
+}
+
+// CleanPath makes p relative to the root of the storage, paths leaving the root are refused.
+func CleanPath(p string) (string, error) {
+	if strings.ContainsAny(p, "\x00\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, p)
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPath, p)
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+p), "/"), nil
+}
+
+// joinURL appends the escaped path to the base URL.
+func joinURL(base, p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(parts, "/")
+}
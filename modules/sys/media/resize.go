@@ -0,0 +1,162 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	ModeFit   = "fit"
+	ModeCrop  = "crop"
+	ModeExact = "exact"
+
+	jpegQuality = 85
+
+	// maxPixels limits the source and the target, decoding allocates width x height x 4 bytes at least
+	// and a small file may declare a huge image
+	maxPixels = 24 << 20
+)
+
+var (
+	ErrUnsupportedImage = errors.New("media: unsupported image")
+	ErrImageTooLarge    = errors.New("media: image is too large")
+)
+
+// Resize decodes a JPEG, PNG or GIF image and encodes it again in the same format at the new size.
+// fit keeps the whole image inside width x height, crop fills width x height and cuts the overflow
+// from the center, exact stretches the image. A zero width or height follows the aspect ratio.
+// The dimensions are read from the header first, images over maxPixels are not decoded.
+func Resize(w io.Writer, r io.Reader, width, height int, mode string) error {
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > maxPixels/cfg.Height {
+		return fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	tw, th := width, height
+	if tw <= 0 {
+		tw = cfg.Width * th / cfg.Height
+	}
+	if th <= 0 {
+		th = cfg.Height * tw / cfg.Width
+	}
+	if tw > 0 && th > maxPixels/tw {
+		return fmt.Errorf("%w: %dx%d", ErrImageTooLarge, tw, th)
+	}
+
+	src, format, err := image.Decode(io.MultiReader(&head, r))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	dst := resize(src, width, height, mode)
+
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, dst, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		return png.Encode(w, dst)
+	case "gif":
+		return gif.Encode(w, dst, nil)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedImage, format)
+}
+
+func resize(src image.Image, width, height int, mode string) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw == 0 || sh == 0 {
+		return src
+	}
+
+	switch {
+	case width <= 0 && height <= 0:
+		return src
+	case width <= 0:
+		width = max(1, sw*height/sh)
+		mode = ModeExact
+	case height <= 0:
+		height = max(1, sh*width/sw)
+		mode = ModeExact
+	}
+
+	crop := b
+	switch mode {
+	case ModeCrop:
+		// the largest part of the source with the aspect ratio of the target
+		cw, ch := sw, sw*height/width
+		if ch > sh {
+			cw, ch = sh*width/height, sh
+		}
+		x, y := b.Min.X+(sw-cw)/2, b.Min.Y+(sh-ch)/2
+		crop = image.Rect(x, y, x+cw, y+ch)
+	case ModeExact:
+	default:
+		if sw*height > sh*width {
+			height = max(1, sh*width/sw)
+		} else {
+			width = max(1, sw*height/sh)
+		}
+	}
+
+	return scale(src, crop, width, height)
+}
+
+// scale averages the source pixels covered by each target pixel (a box filter), which keeps
+// downscaled images smooth. Upscaling repeats pixels.
+func scale(src image.Image, r image.Rectangle, width, height int) *image.NRGBA {
+	in := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(in, in.Bounds(), src, r.Min, draw.Src)
+
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	sw, sh := r.Dx(), r.Dy()
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max(y0+1, (y+1)*sh/height)
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max(x0+1, (x+1)*sw/width)
+
+			var cr, cg, cb, ca, n int
+			for sy := y0; sy < y1; sy++ {
+				i := in.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					a := int(in.Pix[i+3])
+					// weigh colors by alpha so transparent pixels do not darken the edges
+					cr += int(in.Pix[i]) * a
+					cg += int(in.Pix[i+1]) * a
+					cb += int(in.Pix[i+2]) * a
+					ca += a
+					n++
+					i += 4
+				}
+			}
+
+			o := out.PixOffset(x, y)
+			if ca > 0 {
+				out.Pix[o] = uint8(cr / ca)
+				out.Pix[o+1] = uint8(cg / ca)
+				out.Pix[o+2] = uint8(cb / ca)
+			}
+			out.Pix[o+3] = uint8(ca / n)
+		}
+	}
+	return out
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,364 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service     = "s3"
+	s3Algorithm   = "AWS4-HMAC-SHA256"
+	s3EmptyHash   = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3TimeFormat  = "20060102T150405Z"
+	s3DateFormat  = "20060102"
+	s3ListMaxKeys = "1000"
+)
+
+type (
+	s3Storage struct {
+		cfg      config.MediaS3
+		endpoint *url.URL
+		url      string
+		client   *http.Client
+	}
+
+	s3ListResult struct {
+		Contents []struct {
+			Key          string    `xml:"Key"`
+			Size         int64     `xml:"Size"`
+			LastModified time.Time `xml:"LastModified"`
+		} `xml:"Contents"`
+		CommonPrefixes []struct {
+			Prefix string `xml:"Prefix"`
+		} `xml:"CommonPrefixes"`
+		IsTruncated           bool   `xml:"IsTruncated"`
+		NextContinuationToken string `xml:"NextContinuationToken"`
+	}
+
+	s3Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+)
+
+// NewS3 creates a storage for Amazon S3 or a compatible server such as MinIO. Requests are signed
+// with AWS Signature Version 4. Without a public url the files are linked through the endpoint.
+func NewS3(cfg config.MediaS3, publicURL string) (Storage, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("media s3: invalid endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("media s3: bucket is required")
+	}
+
+	s := &s3Storage{
+		cfg:      cfg,
+		endpoint: endpoint,
+		url:      publicURL,
+		client:   &http.Client{Timeout: time.Minute},
+	}
+	if s.url == "" || strings.HasPrefix(s.url, "/") {
+		s.url = strings.TrimSuffix(s.objectURL("", nil).String(), "/")
+	}
+	return s, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, p string, r io.Reader, _ int64, contentType string) error {
+	// the payload is hashed for the signature, uploads are bounded by media.max_size
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("media s3: %w", err)
+	}
+
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	res, err := s.do(ctx, http.MethodPut, p, nil, header, b)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return s.check(res, p)
+}
+
+func (s *s3Storage) Get(ctx context.Context, p string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, p, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.check(res, p); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *s3Storage) Stat(ctx context.Context, p string) (File, error) {
+	res, err := s.do(ctx, http.MethodHead, p, nil, nil, nil)
+	if err != nil {
+		return File{}, err
+	}
+	defer res.Body.Close()
+	if err = s.check(res, p); err != nil {
+		return File{}, err
+	}
+
+	f := File{Path: p, Name: path.Base(p), URL: s.URL(p)}
+	f.Size, _ = strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64)
+	f.ModTime, _ = http.ParseTime(res.Header.Get("Last-Modified"))
+	return f, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, p string) error {
+	if _, err := s.Stat(ctx, p); err != nil {
+		return err
+	}
+
+	res, err := s.do(ctx, http.MethodDelete, p, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return s.check(res, p)
+}
+
+// List uses ListObjectsV2 with the "/" delimiter, common prefixes are the directories.
+func (s *s3Storage) List(ctx context.Context, dir string) ([]File, error) {
+	prefix := ""
+	if dir != "" {
+		prefix = strings.TrimSuffix(dir, "/") + "/"
+	}
+
+	var (
+		files []File
+		token string
+	)
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"delimiter": {"/"},
+			"prefix":    {prefix},
+			"max-keys":  {s3ListMaxKeys},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		res, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		if err = s.check(res, dir); err == nil {
+			err = xml.NewDecoder(res.Body).Decode(&result)
+		}
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("media s3: %w", err)
+		}
+
+		for _, cp := range result.CommonPrefixes {
+			p := strings.TrimSuffix(cp.Prefix, "/")
+			if !strings.HasPrefix(path.Base(p), ".") {
+				files = append(files, File{Path: p, Name: path.Base(p), IsDir: true})
+			}
+		}
+		for _, c := range result.Contents {
+			if c.Key == prefix || strings.HasPrefix(path.Base(c.Key), ".") {
+				continue
+			}
+			files = append(files, File{
+				Path:    c.Key,
+				Name:    path.Base(c.Key),
+				Size:    c.Size,
+				ModTime: c.LastModified,
+				URL:     s.URL(c.Key),
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+func (s *s3Storage) URL(p string) string {
+	return joinURL(s.url, p)
+}
+
+func (s *s3Storage) objectURL(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	escaped := ""
+	if key != "" {
+		escaped = s3Escape(key, false)
+	}
+	if s.cfg.PathStyle {
+		u.Path = "/" + s.cfg.Bucket + "/" + key
+		u.RawPath = "/" + s3Escape(s.cfg.Bucket, false) + "/" + escaped
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + key
+		u.RawPath = "/" + escaped
+	}
+	u.RawQuery = s3Query(query)
+	return &u
+}
+
+func (s *s3Storage) do(ctx context.Context, method, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := s.objectURL(key, query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("media s3: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+
+	s.sign(req, u, body, time.Now().UTC())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("media s3: %w", err)
+	}
+	return res, nil
+}
+
+func (s *s3Storage) check(res *http.Response, p string) error {
+	if res.StatusCode < 300 {
+		return nil
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, p)
+	}
+
+	var e s3Error
+	if b, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10)); len(b) > 0 {
+		_ = xml.Unmarshal(b, &e)
+	}
+	if e.Code == "" {
+		e.Code = res.Status
+	}
+	return fmt.Errorf("media s3: %s: %s %s", p, e.Code, e.Message)
+}
+
+// sign adds the Authorization header of AWS Signature Version 4.
+func (s *s3Storage) sign(req *http.Request, u *url.URL, body []byte, now time.Time) {
+	payloadHash := s3EmptyHash
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": u.Host}
+	for k, v := range req.Header {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "x-amz-") || lk == "content-type" {
+			headers[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		u.EscapedPath(),
+		u.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := now.Format(s3DateFormat)
+	scope := date + "/" + s.cfg.Region + "/" + s3Service + "/aws4_request"
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, now.Format(s3TimeFormat), scope, hex.EncodeToString(sum[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape is the URI encoding of SigV4: everything but A-Z, a-z, 0-9, '-', '.', '_' and '~' is
+// percent-encoded, '/' too unless it separates the segments of a key.
+func s3Escape(s string, slash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !slash:
+			b.WriteByte(c)
+		default:
+			b.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+	return b.String()
+}
+
+// s3Query is the canonical query string: sorted by name, names and values encoded with s3Escape.
+func s3Query(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
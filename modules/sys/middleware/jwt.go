@@ -1,20 +1,40 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"github.com/iagapie/go-spring/modules/sys/config"
 	"github.com/iagapie/go-spring/modules/sys/token"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"time"
 )
 
-func JWT(cfg config.JWT, t token.Token) echo.MiddlewareFunc {
+// RevokedFunc reports whether a valid access token was revoked, e.g. because the password was reset.
+type RevokedFunc func(ctx context.Context, data interface{}, issuedAt time.Time) bool
+
+// JWT accepts only access tokens: the token manager has to check the audience, and the data of
+// the reset and verification tokens, a map with an action, is rejected as well. revoked may be nil.
+func JWT(cfg config.JWT, t token.Token, revoked RevokedFunc) echo.MiddlewareFunc {
 	jwtCfg := middleware.JWTConfig{
 		Skipper: middleware.DefaultSkipper,
 		ContextKey: cfg.ContextKey,
 		TokenLookup: cfg.TokenLookup,
 		AuthScheme: cfg.AuthScheme,
 		ParseTokenFunc: func(auth string, c echo.Context) (interface{}, error) {
-			return t.Validate(auth)
+			claims, err := t.Parse(auth)
+			if err != nil {
+				return nil, err
+			}
+			if data, ok := claims.Data.(map[string]interface{}); ok {
+				if _, ok = data["act"]; ok {
+					return nil, errors.New("jwt: not an access token")
+				}
+			}
+			if revoked != nil && revoked(c.Request().Context(), claims.Data, claims.IssuedAt) {
+				return nil, errors.New("jwt: token was revoked")
+			}
+			return claims.Data, nil
 		},
 	}
 	return middleware.JWTWithConfig(jwtCfg)
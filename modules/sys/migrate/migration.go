@@ -0,0 +1,127 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// SourceCore is the source of the migrations embedded in the binary.
+	SourceCore = "core"
+
+	versionLayout = "20060102150405"
+)
+
+var (
+	fileRegex = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.(up|down)\.sql$`)
+	nameRegex = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type (
+	Migration struct {
+		Source  string
+		Version string
+		Name    string
+		Up      string
+		Down    string
+	}
+
+	// PluginRegisterMigrations is implemented by plugins shipping their own tables.
+	// The returned file system holds <version>_<name>.up.sql and <version>_<name>.down.sql files.
+	PluginRegisterMigrations interface {
+		RegisterMigrations() fs.FS
+	}
+)
+
+func (m Migration) ID() string {
+	return m.Version + "_" + m.Name
+}
+
+// Load reads the migrations found in the root of fsys, ordered by version.
+func Load(source string, fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %s: %w", source, err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	hasUp := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", source, err)
+		}
+
+		m, ok := byVersion[match[1]]
+		if !ok {
+			m = &Migration{Source: source, Version: match[1], Name: match[2]}
+			byVersion[match[1]] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: %s: version %s is used by %s and %s", source, match[1], m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+			hasUp[m.Version] = true
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if !hasUp[m.Version] {
+			return nil, fmt.Errorf("migrate: %s: %s has no up migration", source, m.ID())
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create writes an empty up/down migration pair into dir and returns the file paths.
+func Create(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = nameRegex.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migrate: migration name is empty")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("migrate: %w", err)
+	}
+
+	prefix := filepath.Join(dir, time.Now().UTC().Format(versionLayout)+"_"+name)
+	up, down := prefix+".up.sql", prefix+".down.sql"
+
+	for _, file := range []string{up, down} {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("migrate: %w", err)
+		}
+		if err = f.Close(); err != nil {
+			return "", "", fmt.Errorf("migrate: %w", err)
+		}
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,330 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// lockKey is the key of the advisory lock held while migrations are applied or rolled back.
+const lockKey int64 = 0x6d696772617465 // "migrate"
+
+const createTable = `CREATE TABLE IF NOT EXISTS migrations
+(
+    id         BIGSERIAL PRIMARY KEY,
+    source     VARCHAR(255) NOT NULL,
+    version    VARCHAR(14)  NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    batch      INTEGER      NOT NULL,
+    applied_at TIMESTAMPTZ  NOT NULL,
+    UNIQUE (source, version)
+)`
+
+type (
+	Record struct {
+		ID        uint      `gorm:"primaryKey"`
+		Source    string    `gorm:"size:255"`
+		Version   string    `gorm:"size:14"`
+		Name      string    `gorm:"size:255"`
+		Batch     int       `gorm:"index"`
+		AppliedAt time.Time `gorm:"index"`
+	}
+
+	Status struct {
+		Migration
+		Batch     int
+		AppliedAt *time.Time
+	}
+
+	Migrator struct {
+		db         *postgresdb.Database
+		log        echo.Logger
+		sources    []string
+		migrations map[string][]Migration
+	}
+)
+
+func (Record) TableName() string {
+	return "migrations"
+}
+
+func New(db *postgresdb.Database, log echo.Logger) *Migrator {
+	return &Migrator{
+		db:         db,
+		log:        log,
+		migrations: make(map[string][]Migration),
+	}
+}
+
+// Add registers the migrations of a source. Sources are migrated in the order they were added.
+func (m *Migrator) Add(source string, fsys fs.FS) error {
+	if _, ok := m.migrations[source]; ok {
+		return fmt.Errorf("migrate: source %s is already added", source)
+	}
+
+	migrations, err := Load(source, fsys)
+	if err != nil {
+		return err
+	}
+
+	m.sources = append(m.sources, source)
+	m.migrations[source] = migrations
+	return nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Status
+	for _, source := range m.sources {
+		for _, migration := range m.migrations[source] {
+			item := Status{Migration: migration}
+			if r, ok := applied[key(source, migration.Version)]; ok {
+				appliedAt := r.AppliedAt
+				item.Batch = r.Batch
+				item.AppliedAt = &appliedAt
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	items, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, item := range items {
+		if item.AppliedAt == nil {
+			pending = append(pending, item.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies all pending migrations as one new batch. Each migration runs in its own transaction.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	err = m.lock(ctx, func() error {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		done, err = m.up(ctx, pending)
+		return err
+	})
+	return done, err
+}
+
+// Migrate adds the source when it is not known yet and applies only its pending migrations.
+func (m *Migrator) Migrate(ctx context.Context, source string, fsys fs.FS) ([]Migration, error) {
+	if !m.Has(source) {
+		if err := m.Add(source, fsys); err != nil {
+			return nil, err
+		}
+	}
+
+	var done []Migration
+	err := m.lock(ctx, func() error {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+
+		var own []Migration
+		for _, migration := range pending {
+			if migration.Source == source {
+				own = append(own, migration)
+			}
+		}
+		done, err = m.up(ctx, own)
+		return err
+	})
+	return done, err
+}
+
+// Down rolls back the given number of migrations, or the whole last batch when steps is zero.
+func (m *Migrator) Down(ctx context.Context, steps int) (done []Migration, err error) {
+	err = m.lock(ctx, func() error {
+		if err := m.init(ctx); err != nil {
+			return err
+		}
+
+		var records []Record
+		query := m.db.WithContext(ctx).Order("batch DESC, id DESC")
+		if steps > 0 {
+			query = query.Limit(steps)
+		} else {
+			query = query.Where("batch = (?)", m.db.Model(&Record{}).Select("MAX(batch)"))
+		}
+		if err := query.Find(&records).Error; err != nil {
+			return fmt.Errorf("failed to execute query. error: %w", err)
+		}
+
+		done, err = m.down(ctx, records)
+		return err
+	})
+	return done, err
+}
+
+// Rollback rolls back every applied migration of the source.
+func (m *Migrator) Rollback(ctx context.Context, source string) (done []Migration, err error) {
+	err = m.lock(ctx, func() error {
+		if err := m.init(ctx); err != nil {
+			return err
+		}
+
+		var records []Record
+		if err := m.db.WithContext(ctx).Where("source = ?", source).Order("batch DESC, id DESC").Find(&records).Error; err != nil {
+			return fmt.Errorf("failed to execute query. error: %w", err)
+		}
+
+		done, err = m.down(ctx, records)
+		return err
+	})
+	return done, err
+}
+
+func (m *Migrator) Has(source string) bool {
+	_, ok := m.migrations[source]
+	return ok
+}
+
+func (m *Migrator) up(ctx context.Context, pending []Migration) ([]Migration, error) {
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	var batch int
+	if err := m.db.WithContext(ctx).Model(&Record{}).Select("COALESCE(MAX(batch), 0)").Scan(&batch).Error; err != nil {
+		return nil, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	batch++
+
+	done := make([]Migration, 0, len(pending))
+	for _, migration := range pending {
+		m.log.Infof("migrate: applying %s %s", migration.Source, migration.ID())
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if strings.TrimSpace(migration.Up) != "" {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Create(&Record{
+				Source:    migration.Source,
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Batch:     batch,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrate: %s %s: %w", migration.Source, migration.ID(), err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) down(ctx context.Context, records []Record) ([]Migration, error) {
+	done := make([]Migration, 0, len(records))
+	for _, r := range records {
+		migration, ok := m.find(r.Source, r.Version)
+		if !ok {
+			return done, fmt.Errorf("migrate: %s %s_%s is applied but its files are missing", r.Source, r.Version, r.Name)
+		}
+
+		m.log.Infof("migrate: rolling back %s %s", migration.Source, migration.ID())
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if strings.TrimSpace(migration.Down) != "" {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&Record{}, r.ID).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrate: %s %s: %w", migration.Source, migration.ID(), err)
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) find(source, version string) (Migration, bool) {
+	for _, migration := range m.migrations[source] {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]Record, error) {
+	if err := m.init(ctx); err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if err := m.db.WithContext(ctx).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+
+	applied := make(map[string]Record, len(records))
+	for _, r := range records {
+		applied[key(r.Source, r.Version)] = r
+	}
+	return applied, nil
+}
+
+// lock runs fn while holding a session level advisory lock, so replicas starting at the same time, or the plugin
+// migrations applied during the web startup, never apply or roll back a migration twice. The lock is taken on
+// a connection of its own and released when fn returns, or by the server when the connection is lost.
+func (m *Migrator) lock(ctx context.Context, fn func() error) error {
+	sqlDB, err := m.db.DB.DB()
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("migrate: failed to acquire the migrations lock. error: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			m.log.Error(fmt.Errorf("migrate: failed to release the migrations lock. error: %w", err))
+		}
+	}()
+
+	return fn()
+}
+
+func (m *Migrator) init(ctx context.Context) error {
+	if err := m.db.WithContext(ctx).Exec(createTable).Error; err != nil {
+		return fmt.Errorf("migrate: failed to create migrations table. error: %w", err)
+	}
+	return nil
+}
+
+func key(source, version string) string {
+	return source + "@" + version
+}
@@ -1,12 +1,18 @@
 package plugin
 
 import (
+	"fmt"
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/labstack/echo/v4"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"plugin"
+	"strings"
 )
 
+const dAssets = "assets"
+
 type (
 	Details struct {
 		Code        string `env-required:"required" env:"CODE" yaml:"code" json:"code"`
@@ -17,6 +23,10 @@ type (
 		Icon        string `env:"ICON" yaml:"icon" json:"icon"`
 		IconSVG     string `env:"ICON_SVG" yaml:"icon_svg" json:"icon_svg"`
 		Homepage    string `env:"HOMEPAGE" yaml:"homepage" json:"homepage"`
+		// Requires maps the codes of the plugins this one depends on to semver constraints, e.g. "^1.2".
+		Requires map[string]string `yaml:"requires" json:"requires,omitempty"`
+		// Spring is the minimum Spring version, or a semver constraint on it.
+		Spring string `yaml:"spring" json:"spring,omitempty"`
 	}
 
 	Plugin interface {
@@ -31,9 +41,20 @@ type (
 		Routes(f *spring.Frontend, b *spring.Backend)
 	}
 
+	// FileSystem is implemented by plugins which bring their own files, e.g. component partials.
+	// The root of the file system is the plugin directory.
+	FileSystem interface {
+		FS() fs.FS
+	}
+
 	Info interface {
 		File() string
 		Dir() string
+		FS() fs.FS
+		Namespace() string
+		AssetsURI() string
+		AssetsFS() fs.FS
+		IsStatic() bool
 		Plugin() Plugin
 		Register(s *spring.Spring)
 		Routes(f *spring.Frontend, b *spring.Backend)
@@ -52,9 +73,61 @@ func (i *info) File() string {
 }
 
 func (i *info) Dir() string {
+	if i.IsStatic() {
+		return ""
+	}
 	return filepath.Dir(i.file)
 }
 
+// FS returns the files of the plugin. A .so plugin uses its directory unless it implements FileSystem,
+// a static plugin has no files unless it implements FileSystem.
+func (i *info) FS() fs.FS {
+	if p, ok := i.plug.(FileSystem); ok {
+		return p.FS()
+	}
+	if i.IsStatic() {
+		return nil
+	}
+	return os.DirFS(i.Dir())
+}
+
+// Namespace is <author>/<name>, taken from the plugin directory, or from the code of a static plugin.
+func (i *info) Namespace() string {
+	if dir := i.Dir(); dir != "" {
+		return filepath.Base(filepath.Dir(dir)) + "/" + filepath.Base(dir)
+	}
+	code := i.plug.Details().Code
+	if idx := strings.Index(code, "_"); idx > 0 {
+		return code[:idx] + "/" + code[idx+1:]
+	}
+	return code
+}
+
+// AssetsURI is where the assets/ directory of the plugin is served.
+func (i *info) AssetsURI() string {
+	return fmt.Sprintf("/plugins/%s/%s", i.Namespace(), dAssets)
+}
+
+// AssetsFS returns the assets/ directory of the plugin, nil when the plugin has none.
+func (i *info) AssetsFS() fs.FS {
+	fsys := i.FS()
+	if fsys == nil {
+		return nil
+	}
+	if fi, err := fs.Stat(fsys, dAssets); err != nil || !fi.IsDir() {
+		return nil
+	}
+	sub, err := fs.Sub(fsys, dAssets)
+	if err != nil {
+		return nil
+	}
+	return sub
+}
+
+func (i *info) IsStatic() bool {
+	return i.file == ""
+}
+
 func (i *info) Plugin() Plugin {
 	return i.plug
 }
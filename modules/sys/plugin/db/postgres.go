@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+var _ plugin.Storage = &storage{}
+
+type storage struct {
+	db  *postgresdb.Database
+	log *logrus.Entry
+}
+
+func NewStorage(postgres *postgresdb.Database, log *logrus.Entry) plugin.Storage {
+	return &storage{
+		db:  postgres,
+		log: log,
+	}
+}
+
+func (s *storage) All(ctx context.Context) ([]plugin.State, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var states []plugin.State
+	if err := s.db.WithContext(ctx).Order("code").Find(&states).Error; err != nil {
+		// the plugins table is created by a core migration, no plugin is installed before it is applied
+		if strings.Contains(err.Error(), "42P01") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to execute query. error: %w", err)
+	}
+	return states, nil
+}
+
+func (s *storage) Save(ctx context.Context, state plugin.State) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = now
+	}
+	state.UpdatedAt = now
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"version", "installed", "enabled", "updated_at"}),
+	}).Create(&state).Error
+	if err != nil {
+		return fmt.Errorf("failed to execute query. error: %w", err)
+	}
+
+	s.log.Tracef("Saved plugin state: %s.\n", state.Code)
+
+	return nil
+}
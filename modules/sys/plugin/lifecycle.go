@@ -0,0 +1,223 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/migrate"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"strings"
+)
+
+// Boot loads the persisted plugin states. The migrations of installed plugins are added to the migrator.
+// Until Boot is called every loaded plugin counts as enabled.
+func (m *Manager) Boot(ctx context.Context, storage Storage, db *postgresdb.Database, migrator *migrate.Migrator) error {
+	states, err := storage.All(ctx)
+	if err != nil {
+		return fmt.Errorf("plugin manager: %w", err)
+	}
+
+	m.storage = storage
+	m.db = db
+	m.migrator = migrator
+	m.states = make(map[string]State, len(states))
+	for _, state := range states {
+		m.states[state.Code] = state
+	}
+
+	for code, i := range m.codeMap {
+		if !m.IsInstalled(code) {
+			continue
+		}
+		if regMigrations, ok := i.Plugin().(migrate.PluginRegisterMigrations); ok && !migrator.Has(code) {
+			if err = migrator.Add(code, regMigrations.RegisterMigrations()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// InstallNew installs the loaded plugins which have no state yet, so dropping a new plugin in is enough.
+func (m *Manager) InstallNew(ctx context.Context) error {
+	for _, i := range m.order {
+		code := i.Plugin().Details().Code
+		if _, ok := m.states[code]; ok {
+			continue
+		}
+		if err := m.Install(ctx, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) States() map[string]State {
+	return m.states
+}
+
+func (m *Manager) State(code string) (State, bool) {
+	state, ok := m.states[code]
+	return state, ok
+}
+
+func (m *Manager) IsInstalled(code string) bool {
+	if m.states == nil {
+		return true
+	}
+	return m.states[code].Installed
+}
+
+func (m *Manager) IsEnabled(code string) bool {
+	if m.states == nil {
+		return true
+	}
+	state := m.states[code]
+	return state.Installed && state.Enabled
+}
+
+// Install runs the plugin migrations and its Install method, then enables it.
+func (m *Manager) Install(ctx context.Context, code string) error {
+	i, err := m.lifecycle(code)
+	if err != nil {
+		return err
+	}
+
+	if err = m.checkRequired(code); err != nil {
+		return err
+	}
+
+	details := i.Plugin().Details()
+	m.log.Infof("plugin manager: installing %s[%s] %s", details.Name, code, details.Version)
+
+	if regMigrations, ok := i.Plugin().(migrate.PluginRegisterMigrations); ok {
+		if _, err = m.migrator.Migrate(ctx, code, regMigrations.RegisterMigrations()); err != nil {
+			return err
+		}
+	}
+
+	if p, ok := i.Plugin().(Installer); ok {
+		if err = p.Install(ctx, m.db); err != nil {
+			return fmt.Errorf("plugin manager: install %s: %w", code, err)
+		}
+	}
+	if p, ok := i.Plugin().(Enabler); ok {
+		if err = p.Enable(ctx, m.db); err != nil {
+			return fmt.Errorf("plugin manager: enable %s: %w", code, err)
+		}
+	}
+
+	return m.save(ctx, code, details.Version, true, true)
+}
+
+// Uninstall runs the plugin Uninstall method and rolls back all of its migrations.
+// The plugin stays uninstalled until it is enabled again.
+func (m *Manager) Uninstall(ctx context.Context, code string) error {
+	i, err := m.lifecycle(code)
+	if err != nil {
+		return err
+	}
+	if !m.IsInstalled(code) {
+		return fmt.Errorf("plugin manager: plugin %s is not installed", code)
+	}
+	if dependents := m.requiredBy(code); len(dependents) > 0 {
+		return fmt.Errorf("plugin manager: plugin %s is required by %s", code, strings.Join(dependents, ", "))
+	}
+
+	details := i.Plugin().Details()
+	m.log.Infof("plugin manager: uninstalling %s[%s]", details.Name, code)
+
+	if m.IsEnabled(code) {
+		if p, ok := i.Plugin().(Disabler); ok {
+			if err = p.Disable(ctx, m.db); err != nil {
+				return fmt.Errorf("plugin manager: disable %s: %w", code, err)
+			}
+		}
+	}
+	if p, ok := i.Plugin().(Uninstaller); ok {
+		if err = p.Uninstall(ctx, m.db); err != nil {
+			return fmt.Errorf("plugin manager: uninstall %s: %w", code, err)
+		}
+	}
+
+	if _, ok := i.Plugin().(migrate.PluginRegisterMigrations); ok {
+		if _, err = m.migrator.Rollback(ctx, code); err != nil {
+			return err
+		}
+	}
+
+	return m.save(ctx, code, details.Version, false, false)
+}
+
+// Enable installs the plugin first when it is not installed.
+func (m *Manager) Enable(ctx context.Context, code string) error {
+	i, err := m.lifecycle(code)
+	if err != nil {
+		return err
+	}
+	if !m.IsInstalled(code) {
+		return m.Install(ctx, code)
+	}
+	if m.IsEnabled(code) {
+		return nil
+	}
+	if err = m.checkRequired(code); err != nil {
+		return err
+	}
+
+	if p, ok := i.Plugin().(Enabler); ok {
+		if err = p.Enable(ctx, m.db); err != nil {
+			return fmt.Errorf("plugin manager: enable %s: %w", code, err)
+		}
+	}
+
+	return m.save(ctx, code, i.Plugin().Details().Version, true, true)
+}
+
+func (m *Manager) Disable(ctx context.Context, code string) error {
+	i, err := m.lifecycle(code)
+	if err != nil {
+		return err
+	}
+	if !m.IsEnabled(code) {
+		return nil
+	}
+	if dependents := m.requiredBy(code); len(dependents) > 0 {
+		return fmt.Errorf("plugin manager: plugin %s is required by %s", code, strings.Join(dependents, ", "))
+	}
+
+	if p, ok := i.Plugin().(Disabler); ok {
+		if err = p.Disable(ctx, m.db); err != nil {
+			return fmt.Errorf("plugin manager: disable %s: %w", code, err)
+		}
+	}
+
+	return m.save(ctx, code, i.Plugin().Details().Version, true, false)
+}
+
+func (m *Manager) lifecycle(code string) (Info, error) {
+	if m.storage == nil {
+		return nil, fmt.Errorf("plugin manager: plugin states are not loaded")
+	}
+	i, ok := m.GetByCode(code)
+	if !ok {
+		return nil, fmt.Errorf("plugin manager: plugin %s not found", code)
+	}
+	return i, nil
+}
+
+func (m *Manager) save(ctx context.Context, code, version string, installed, enabled bool) error {
+	state, ok := m.states[code]
+	if !ok {
+		state = State{Code: code}
+	}
+	state.Version = version
+	state.Installed = installed
+	state.Enabled = enabled
+
+	if err := m.storage.Save(ctx, state); err != nil {
+		return fmt.Errorf("plugin manager: %w", err)
+	}
+	m.states[code] = state
+	return nil
+}
@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/migrate"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/labstack/echo/v4"
 	"io/fs"
@@ -11,25 +13,50 @@ import (
 )
 
 type Manager struct {
-	log     echo.Logger
-	infoMap map[string]Info
-	codeMap map[string]*info
+	log      echo.Logger
+	infoMap  map[string]Info
+	codeMap  map[string]*info
+	order    []*info
+	storage  Storage
+	db       *postgresdb.Database
+	migrator *migrate.Migrator
+	states   map[string]State
 }
 
-func New(pluginsPath string, log echo.Logger) (*Manager, error) {
+// Loader creates plugins from files under the plugins path other than .so files.
+type Loader interface {
+	Match(path string) bool
+	Load(path string) (Plugin, error)
+}
+
+func New(pluginsPath string, log echo.Logger, loaders ...Loader) (*Manager, error) {
 	m := &Manager{
 		log:     log,
 		infoMap: make(map[string]Info),
 		codeMap: make(map[string]*info),
 	}
 
+	for _, p := range Static() {
+		if _, err := m.add(staticPrefix+p.Details().Code, &info{log: m.log, plug: p}); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := filepath.Walk(pluginsPath, func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
 		if strings.HasSuffix(path, ".so") {
-			if _, err = m.GetByFile(path); err != nil {
+			if _, err = m.load(path); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		for _, loader := range loaders {
+			if loader.Match(path) {
+				_, err = m.loadWith(loader, path)
 				return err
 			}
 		}
@@ -39,14 +66,68 @@ func New(pluginsPath string, log echo.Logger) (*Manager, error) {
 		return nil, err
 	}
 
+	if err := m.resolve(); err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
 
+// All returns every loaded plugin, disabled ones included.
 func (m *Manager) All() map[string]Info {
 	return m.infoMap
 }
 
+// Ordered returns every loaded plugin, dependencies before the plugins requiring them.
+func (m *Manager) Ordered() []Info {
+	ordered := make([]Info, 0, len(m.order))
+	for _, i := range m.order {
+		ordered = append(ordered, i)
+	}
+	return ordered
+}
+
+// Enabled returns the loaded plugins which are installed and enabled, in dependency order.
+func (m *Manager) Enabled() []Info {
+	enabled := make([]Info, 0, len(m.order))
+	for _, i := range m.order {
+		if m.IsEnabled(i.Plugin().Details().Code) {
+			enabled = append(enabled, i)
+		}
+	}
+	return enabled
+}
+
+func (m *Manager) GetByCode(code string) (Info, bool) {
+	i, ok := m.codeMap[code]
+	if !ok {
+		return nil, false
+	}
+	return i, true
+}
+
+// GetByFile loads the plugin file unless it is already loaded. The dependencies of the plugin must be loaded before.
 func (m *Manager) GetByFile(file string) (Info, error) {
+	if _, ok := m.infoMap[file]; ok {
+		return m.infoMap[file], nil
+	}
+
+	i, err := m.load(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.resolve(); err != nil {
+		code := i.Plugin().Details().Code
+		delete(m.infoMap, file)
+		delete(m.codeMap, code)
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (m *Manager) load(file string) (Info, error) {
 	if p, ok := m.infoMap[file]; ok {
 		return p, nil
 	}
@@ -61,36 +142,46 @@ func (m *Manager) GetByFile(file string) (Info, error) {
 		return nil, err
 	}
 
-	i := &info{
+	return m.add(file, &info{
 		log:  m.log,
 		p:    p,
 		plug: *plug.(*Plugin),
 		file: file,
+	})
+}
+
+func (m *Manager) loadWith(loader Loader, file string) (Info, error) {
+	p, err := loader.Load(file)
+	if err != nil {
+		return nil, err
 	}
+	return m.add(file, &info{log: m.log, plug: p, file: file})
+}
 
+func (m *Manager) add(key string, i *info) (Info, error) {
 	details := i.Plugin().Details()
 	code := details.Code
 
 	if _, ok := m.codeMap[code]; ok {
-		return nil, fmt.Errorf("plugin manager: plugin %s - %s not unique", file, code)
+		return nil, fmt.Errorf("plugin manager: plugin %s - %s not unique", key, code)
 	}
 
-	m.infoMap[file] = i
+	m.infoMap[key] = i
 	m.codeMap[code] = i
 
 	m.log.Debugf("plugin manager: %s[%s] - %s was loaded successfully", details.Name, details.Code, details.Version)
 
-	return m.infoMap[file], nil
+	return i, nil
 }
 
 func (m *Manager) RegisterAll(s *spring.Spring) {
-	for _, i := range m.infoMap {
+	for _, i := range m.Enabled() {
 		i.Register(s)
 	}
 }
 
 func (m *Manager) RoutesAll(f *spring.Frontend, b *spring.Backend) {
-	for _, i := range m.infoMap {
+	for _, i := range m.Enabled() {
 		i.Routes(f, b)
 	}
 }
@@ -0,0 +1,103 @@
+package remote
+
+import (
+	"context"
+	"github.com/iagapie/go-spring/modules/cms/component"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// remoteComponent forwards OnRun, OnRender and the On* ajax handlers to the plugin process.
+type remoteComponent struct {
+	*component.CompBase
+	plugin   *remotePlugin
+	spec     ComponentSpec
+	handlers map[string]bool
+}
+
+var _ component.HandlerCaller = (*remoteComponent)(nil)
+
+func newComponent(p *remotePlugin, spec ComponentSpec, props component.Props) component.Component {
+	handlers := make(map[string]bool, len(spec.Handlers))
+	for _, h := range spec.Handlers {
+		handlers[h] = true
+	}
+	return &remoteComponent{
+		CompBase: component.NewCompBase(props),
+		plugin:   p,
+		spec:     spec,
+		handlers: handlers,
+	}
+}
+
+func (c *remoteComponent) Details() component.Details {
+	return c.spec.Details
+}
+
+func (c *remoteComponent) CfgProps() component.CfgProps {
+	return c.spec.CfgProps
+}
+
+func (c *remoteComponent) OnRun(r *http.Request) string {
+	req := &HTTPRequest{
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		Host:       r.Host,
+		RemoteAddr: r.RemoteAddr,
+		Header:     r.Header,
+		Form:       r.Form,
+	}
+
+	res, err := c.call(r.Context(), methodComponentRun, "", req)
+	if err != nil {
+		c.plugin.log.Errorf("remote plugin: %s: %s OnRun: %v", c.plugin.manifest.Code, c.spec.Details.Code, err)
+		return ""
+	}
+	return res.Output
+}
+
+func (c *remoteComponent) OnRender() string {
+	res, err := c.call(context.Background(), methodComponentRender, "", nil)
+	if err != nil {
+		c.plugin.log.Errorf("remote plugin: %s: %s OnRender: %v", c.plugin.manifest.Code, c.spec.Details.Code, err)
+		return ""
+	}
+	return res.Output
+}
+
+func (c *remoteComponent) HasHandler(name string) bool {
+	return c.handlers[name]
+}
+
+func (c *remoteComponent) CallHandler(name string, ctx echo.Context) (interface{}, error) {
+	req, err := newHTTPRequest(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.call(ctx.Request().Context(), methodComponentCall, name, req)
+	if err != nil {
+		return nil, toHTTPError(err)
+	}
+	return res.Result, nil
+}
+
+// call sends the current props and takes over the props returned by the plugin.
+func (c *remoteComponent) call(ctx context.Context, method, handler string, req *HTTPRequest) (ComponentResult, error) {
+	var res ComponentResult
+	err := c.plugin.proc.Call(ctx, method, ComponentCall{
+		Component: c.spec.Details.Code,
+		Alias:     c.Alias(),
+		Handler:   handler,
+		Props:     c.Props(),
+		Request:   req,
+	}, &res)
+	if err != nil {
+		return res, err
+	}
+
+	for k, v := range res.Props {
+		c.SetProp(k, v)
+	}
+	return res, nil
+}
@@ -0,0 +1,77 @@
+package remote
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFile marks a directory under the plugins path as an out-of-process plugin.
+const ManifestFile = "plugin.yml"
+
+type (
+	Manifest struct {
+		plugin.Details `yaml:",inline"`
+		Executable     string            `yaml:"executable"`
+		Args           []string          `yaml:"args"`
+		Transport      string            `yaml:"transport"`
+		Env            map[string]string `yaml:"env"`
+		// Timeout limits every call to the plugin process.
+		Timeout time.Duration `yaml:"timeout"`
+	}
+
+	loader struct {
+		log echo.Logger
+	}
+)
+
+// NewLoader returns a plugin.Loader for the plugin.yml manifests of out-of-process plugins.
+func NewLoader(log echo.Logger) plugin.Loader {
+	return &loader{log: log}
+}
+
+func (l *loader) Match(path string) bool {
+	return filepath.Base(path) == ManifestFile
+}
+
+func (l *loader) Load(path string) (plugin.Plugin, error) {
+	m, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return newPlugin(m, filepath.Dir(path), l.log), nil
+}
+
+func ReadManifest(path string) (Manifest, error) {
+	var m Manifest
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("remote plugin: %w", err)
+	}
+	if err = yaml.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("remote plugin: %s: %w", path, err)
+	}
+
+	if m.Code == "" || m.Name == "" || m.Version == "" {
+		return m, fmt.Errorf("remote plugin: %s: code, name and version are required", path)
+	}
+	if m.Executable == "" {
+		return m, fmt.Errorf("remote plugin: %s: executable is required", path)
+	}
+	if m.Transport == "" {
+		m.Transport = TransportStdio
+	}
+	if m.Transport != TransportStdio && m.Transport != TransportUnix {
+		return m, fmt.Errorf("remote plugin: %s: unknown transport %s", path, m.Transport)
+	}
+	if m.Timeout <= 0 {
+		m.Timeout = 30 * time.Second
+	}
+
+	return m, nil
+}
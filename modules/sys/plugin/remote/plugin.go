@@ -0,0 +1,197 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/component"
+	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/iagapie/go-spring/modules/sys/view"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"net/rpc"
+	"sync"
+)
+
+const maxBodySize = 32 << 20
+
+type remotePlugin struct {
+	mu       sync.RWMutex
+	manifest Manifest
+	log      echo.Logger
+	proc     *process
+	desc     Description
+	err      error
+}
+
+var (
+	_ plugin.Register                    = (*remotePlugin)(nil)
+	_ plugin.Routes                      = (*remotePlugin)(nil)
+	_ component.PluginRegisterComponents = (*remotePlugin)(nil)
+)
+
+func newPlugin(m Manifest, dir string, log echo.Logger) *remotePlugin {
+	p := &remotePlugin{
+		manifest: m,
+		log:      log,
+		proc:     newProcess(m, dir, log),
+	}
+	p.proc.started = p.describe
+	return p
+}
+
+func (p *remotePlugin) Details() plugin.Details {
+	return p.manifest.Details
+}
+
+// Register starts the plugin process. Nothing is started for disabled plugins or commands not registering plugins.
+func (p *remotePlugin) Register(s *spring.Spring) {
+	if err := p.proc.Start(); err != nil {
+		p.mu.Lock()
+		p.err = err
+		p.mu.Unlock()
+		p.log.Error(err)
+		return
+	}
+	if s.Server != nil {
+		s.Server.RegisterOnShutdown(p.proc.Stop)
+	}
+}
+
+func (p *remotePlugin) Routes(f *spring.Frontend, b *spring.Backend) {
+	for _, r := range p.description().Routes {
+		var route *echo.Route
+		h := p.serveHTTP(routeKey(r.Area, r.Method, r.Path))
+		switch r.Area {
+		case AreaFrontend:
+			route = f.Add(r.Method, r.Path, h)
+		case AreaBackend:
+			if r.Public {
+				route = b.Add(r.Method, r.Path, h)
+				break
+			}
+			if b.JWTMiddleware == nil || b.UserMiddleware == nil {
+				p.log.Warnf("remote plugin: %s: route %s %s requires a signed in user, no authentication is configured", p.manifest.Code, r.Method, r.Path)
+				continue
+			}
+			route = b.Add(r.Method, r.Path, h, b.JWTMiddleware, b.UserMiddleware)
+		default:
+			p.log.Warnf("remote plugin: %s: unknown route area %s", p.manifest.Code, r.Area)
+			continue
+		}
+		if r.Name != "" {
+			route.Name = r.Name
+		}
+	}
+}
+
+func (p *remotePlugin) RegisterComponents() component.FactoryMap {
+	factories := make(component.FactoryMap)
+	for _, spec := range p.description().Components {
+		spec := spec
+		factories[spec.Details.Code] = func(_ view.View, props component.Props) (component.Component, error) {
+			return newComponent(p, spec, props), nil
+		}
+	}
+	return factories
+}
+
+func (p *remotePlugin) description() Description {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.desc
+}
+
+// describe runs after every start of the process, a restarted process has to describe the same protocol.
+func (p *remotePlugin) describe(client *rpc.Client) error {
+	var desc Description
+	if err := client.Call(methodDescribe, DescribeArgs{ProtocolVersion: ProtocolVersion}, &desc); err != nil {
+		return fmt.Errorf("describe: %w", err)
+	}
+	if desc.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("protocol version %d is not supported, expected %d", desc.ProtocolVersion, ProtocolVersion)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.desc.ProtocolVersion == 0 {
+		p.desc = desc
+	}
+	return nil
+}
+
+func (p *remotePlugin) serveHTTP(route string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req, err := newHTTPRequest(c, true)
+		if err != nil {
+			return err
+		}
+		req.Route = route
+
+		var res HTTPResponse
+		if err = p.proc.Call(c.Request().Context(), methodServeHTTP, req, &res); err != nil {
+			return toHTTPError(err)
+		}
+
+		for k, values := range res.Header {
+			for _, v := range values {
+				c.Response().Header().Add(k, v)
+			}
+		}
+		if res.Status == 0 {
+			res.Status = http.StatusOK
+		}
+		c.Response().WriteHeader(res.Status)
+		_, err = c.Response().Write(res.Body)
+		return err
+	}
+}
+
+func newHTTPRequest(c echo.Context, withBody bool) (*HTTPRequest, error) {
+	r := c.Request()
+	req := &HTTPRequest{
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		Host:       r.Host,
+		RemoteAddr: c.RealIP(),
+		Header:     r.Header,
+		Params:     make(map[string]string),
+	}
+
+	for i, name := range c.ParamNames() {
+		if i < len(c.ParamValues()) {
+			req.Params[name] = c.ParamValues()[i]
+		}
+	}
+
+	if withBody && r.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > maxBodySize {
+			return nil, echo.ErrStatusRequestEntityTooLarge
+		}
+		req.Body = body
+	} else {
+		// the body of an ajax request is already parsed into the form
+		if r.Form == nil {
+			_ = r.ParseForm()
+		}
+		req.Form = r.Form
+	}
+
+	return req, nil
+}
+
+func toHTTPError(err error) error {
+	switch {
+	case errors.Is(err, ErrUnavailable):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return echo.NewHTTPError(http.StatusGatewayTimeout, err.Error()).SetInternal(err)
+	}
+	return echo.NewHTTPError(http.StatusBadGateway, err.Error()).SetInternal(err)
+}
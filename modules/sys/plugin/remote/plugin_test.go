@@ -0,0 +1,70 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBackendRoutesRequireUser(t *testing.T) {
+	e := echo.New()
+	f := &spring.Frontend{Group: e.Group("")}
+	b := &spring.Backend{Group: e.Group("/backend")}
+
+	p := &remotePlugin{log: log.New("test"), desc: Description{Routes: []RouteSpec{
+		{Area: AreaBackend, Method: http.MethodGet, Path: "/private"},
+		{Area: AreaBackend, Method: http.MethodGet, Path: "/public", Public: true},
+	}}}
+
+	// without authentication only the public route is served
+	p.Routes(f, b)
+	if !routed(e, "/backend/public") || routed(e, "/backend/private") {
+		t.Fatalf("routes %v, want only /backend/public", e.Routes())
+	}
+
+	b.JWTMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return echo.ErrUnauthorized
+		}
+	}
+	b.UserMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc {
+		return next
+	}
+	p.Routes(f, b)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/backend/private", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("GET /backend/private = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequestBodyTooLarge(t *testing.T) {
+	e := echo.New()
+
+	for _, size := range []int{maxBodySize, maxBodySize + 1} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(make([]byte, size)))
+		r, err := newHTTPRequest(e.NewContext(req, httptest.NewRecorder()), true)
+
+		switch {
+		case size > maxBodySize && !errors.Is(err, echo.ErrStatusRequestEntityTooLarge):
+			t.Errorf("body of %d bytes: err = %v, want %v", size, err, echo.ErrStatusRequestEntityTooLarge)
+		case size <= maxBodySize && (err != nil || len(r.Body) != size):
+			t.Errorf("body of %d bytes: err = %v, read %d bytes", size, err, len(r.Body))
+		}
+	}
+}
+
+func routed(e *echo.Echo, path string) bool {
+	for _, r := range e.Routes() {
+		if r.Path == path {
+			return true
+		}
+	}
+	return false
+}
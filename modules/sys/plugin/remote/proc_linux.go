@@ -0,0 +1,11 @@
+package remote
+
+import "syscall"
+
+// the process gets its own group and is killed with the host
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+	}
+}
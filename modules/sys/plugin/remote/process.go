@@ -0,0 +1,304 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	dialTimeout = 10 * time.Second
+	minBackoff  = time.Second
+	maxBackoff  = 30 * time.Second
+	// a process running longer than this is considered healthy and the backoff starts over
+	stableAfter = time.Minute
+)
+
+var (
+	ErrUnavailable = errors.New("remote plugin: process is not running")
+	ErrTimeout     = errors.New("remote plugin: call timed out")
+
+	errStopped = errors.New("stopped while starting")
+)
+
+type (
+	process struct {
+		mu       sync.RWMutex
+		manifest Manifest
+		dir      string
+		log      echo.Logger
+		cmd      *exec.Cmd
+		client   *rpc.Client
+		stopped  bool
+		exited   chan struct{}
+		// started is called after every successful (re)start, with the client of the new process
+		started func(client *rpc.Client) error
+	}
+
+	stdioConn struct {
+		io.Reader
+		io.WriteCloser
+	}
+)
+
+func newProcess(m Manifest, dir string, log echo.Logger) *process {
+	return &process{
+		manifest: m,
+		dir:      dir,
+		log:      log,
+	}
+}
+
+// Start runs the process and supervises it, a crashed process is started again with an increasing backoff.
+func (p *process) Start() error {
+	if err := p.spawn(); err != nil {
+		return err
+	}
+	go p.supervise()
+	return nil
+}
+
+func (p *process) Stop() {
+	p.mu.Lock()
+	p.stopped = true
+	cmd, client, exited := p.cmd, p.client, p.exited
+	p.mu.Unlock()
+
+	if client != nil {
+		_ = client.Close()
+	}
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+
+	_ = cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		<-exited
+	}
+}
+
+func (p *process) Call(ctx context.Context, method string, args, reply interface{}) error {
+	p.mu.RLock()
+	client := p.client
+	p.mu.RUnlock()
+
+	if client == nil {
+		return ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.manifest.Timeout)
+	defer cancel()
+
+	call := client.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if errors.Is(call.Error, rpc.ErrShutdown) || errors.Is(call.Error, io.ErrUnexpectedEOF) {
+			return ErrUnavailable
+		}
+		return call.Error
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ErrTimeout
+		}
+		return ctx.Err()
+	}
+}
+
+func (p *process) spawn() error {
+	executable := p.manifest.Executable
+	if !filepath.IsAbs(executable) {
+		executable = filepath.Join(p.dir, executable)
+	}
+
+	cmd := exec.Command(executable, p.manifest.Args...)
+	cmd.Dir = p.dir
+	cmd.Env = p.env()
+	cmd.SysProcAttr = sysProcAttr()
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return p.errorf("%w", err)
+	}
+	// Wait closes the pipe, it may only be called once everything was read, the crash trace included
+	logged := make(chan struct{})
+	go func() {
+		p.logStderr(stderr)
+		close(logged)
+	}()
+
+	var conn io.ReadWriteCloser
+	var socket string
+
+	switch p.manifest.Transport {
+	case TransportUnix:
+		socket = filepath.Join(os.TempDir(), fmt.Sprintf("spring-%s-%d.sock", p.manifest.Code, time.Now().UnixNano()))
+		cmd.Env = append(cmd.Env, EnvSocket+"="+socket)
+	default:
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return p.errorf("%w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return p.errorf("%w", err)
+		}
+		conn = stdioConn{Reader: stdout, WriteCloser: stdin}
+	}
+
+	if err = cmd.Start(); err != nil {
+		return p.errorf("%w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		<-logged
+		err := cmd.Wait()
+		p.log.Warnf("remote plugin: %s exited: %v", p.manifest.Code, err)
+		if socket != "" {
+			_ = os.Remove(socket)
+		}
+		close(exited)
+	}()
+
+	if socket != "" {
+		if conn, err = dial(socket, exited); err != nil {
+			_ = cmd.Process.Kill()
+			<-exited
+			return p.errorf("%w", err)
+		}
+	}
+
+	client := jsonrpc.NewClient(conn)
+	if p.started != nil {
+		if err = p.started(client); err != nil {
+			_ = client.Close()
+			_ = cmd.Process.Kill()
+			<-exited
+			return p.errorf("%w", err)
+		}
+	}
+
+	p.mu.Lock()
+	if p.stopped {
+		// Stop was called while starting, it did not see this process
+		p.mu.Unlock()
+		_ = client.Close()
+		_ = cmd.Process.Kill()
+		<-exited
+		return p.errorf("%w", errStopped)
+	}
+	p.cmd, p.client, p.exited = cmd, client, exited
+	p.mu.Unlock()
+
+	p.log.Infof("remote plugin: %s started (pid %d)", p.manifest.Code, cmd.Process.Pid)
+	return nil
+}
+
+func (p *process) supervise() {
+	backoff := minBackoff
+	for {
+		p.mu.RLock()
+		exited, startedAt := p.exited, time.Now()
+		p.mu.RUnlock()
+
+		<-exited
+
+		p.mu.Lock()
+		if p.client != nil {
+			_ = p.client.Close()
+			p.client = nil
+		}
+		stopped := p.stopped
+		p.mu.Unlock()
+
+		if stopped {
+			return
+		}
+
+		if time.Since(startedAt) > stableAfter {
+			backoff = minBackoff
+		}
+
+		for {
+			p.log.Warnf("remote plugin: %s crashed, restarting in %s", p.manifest.Code, backoff)
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+
+			p.mu.RLock()
+			stopped = p.stopped
+			p.mu.RUnlock()
+			if stopped {
+				return
+			}
+
+			err := p.spawn()
+			if err == nil {
+				break
+			}
+			if errors.Is(err, errStopped) {
+				return
+			}
+			p.log.Error(err)
+		}
+	}
+}
+
+// env only passes what the plugin needs, the host environment holds database and signing secrets.
+func (p *process) env() []string {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + os.Getenv("HOME"),
+		"TMPDIR=" + os.TempDir(),
+		EnvTransport + "=" + p.manifest.Transport,
+	}
+	for k, v := range p.manifest.Env {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+func (p *process) logStderr(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		p.log.Infof("remote plugin: %s: %s", p.manifest.Code, scanner.Text())
+	}
+}
+
+func (p *process) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("remote plugin: %s: "+format, append([]interface{}{p.manifest.Code}, a...)...)
+}
+
+func dial(socket string, exited <-chan struct{}) (net.Conn, error) {
+	deadline := time.Now().Add(dialTimeout)
+	for {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+
+		select {
+		case <-exited:
+			return nil, errors.New("process exited before listening")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
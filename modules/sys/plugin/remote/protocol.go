@@ -0,0 +1,96 @@
+package remote
+
+import (
+	"github.com/iagapie/go-spring/modules/cms/component"
+	"net/http"
+)
+
+// The host talks JSON-RPC 1.0 (net/rpc/jsonrpc) to the plugin process. The plugin is the server
+// and registers its methods under ServiceName, so any language with a JSON-RPC library can implement it.
+const (
+	ProtocolVersion = 1
+	ServiceName     = "Plugin"
+
+	TransportStdio = "stdio"
+	TransportUnix  = "unix"
+
+	// EnvTransport tells the plugin process which transport to serve.
+	EnvTransport = "SPRING_PLUGIN_TRANSPORT"
+	// EnvSocket is the Unix socket path the plugin has to listen on when the transport is unix.
+	EnvSocket = "SPRING_PLUGIN_SOCKET"
+
+	AreaFrontend = "frontend"
+	AreaBackend  = "backend"
+
+	methodDescribe        = ServiceName + ".Describe"
+	methodServeHTTP       = ServiceName + ".ServeHTTP"
+	methodComponentRun    = ServiceName + ".ComponentRun"
+	methodComponentRender = ServiceName + ".ComponentRender"
+	methodComponentCall   = ServiceName + ".ComponentHandler"
+)
+
+type (
+	DescribeArgs struct {
+		ProtocolVersion int `json:"protocol_version"`
+	}
+
+	Description struct {
+		ProtocolVersion int             `json:"protocol_version"`
+		Routes          []RouteSpec     `json:"routes"`
+		Components      []ComponentSpec `json:"components"`
+	}
+
+	RouteSpec struct {
+		Area   string `json:"area"`
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Name   string `json:"name,omitempty"`
+		// Public backend routes are served to anyone, the others require a signed in backend user.
+		// The plugin can not check the Authorization header itself, it has no signing keys.
+		Public bool `json:"public,omitempty"`
+	}
+
+	ComponentSpec struct {
+		Details  component.Details  `json:"details"`
+		CfgProps component.CfgProps `json:"cfg_props,omitempty"`
+		Handlers []string           `json:"handlers,omitempty"`
+	}
+
+	HTTPRequest struct {
+		// Route identifies the route of a ServeHTTP call, it is "<area> <method> <path>" of the RouteSpec.
+		Route      string              `json:"route,omitempty"`
+		Method     string              `json:"method"`
+		URL        string              `json:"url"`
+		Host       string              `json:"host"`
+		RemoteAddr string              `json:"remote_addr"`
+		Header     http.Header         `json:"header,omitempty"`
+		Form       map[string][]string `json:"form,omitempty"`
+		Params     map[string]string   `json:"params,omitempty"`
+		Body       []byte              `json:"body,omitempty"`
+	}
+
+	HTTPResponse struct {
+		Status int         `json:"status"`
+		Header http.Header `json:"header,omitempty"`
+		Body   []byte      `json:"body,omitempty"`
+	}
+
+	ComponentCall struct {
+		Component string          `json:"component"`
+		Alias     string          `json:"alias"`
+		Handler   string          `json:"handler,omitempty"`
+		Props     component.Props `json:"props,omitempty"`
+		Request   *HTTPRequest    `json:"request,omitempty"`
+	}
+
+	ComponentResult struct {
+		Output string          `json:"output,omitempty"`
+		Props  component.Props `json:"props,omitempty"`
+		// Result is the value returned by an On* handler, it is merged into the ajax response like a Go handler result.
+		Result interface{} `json:"result,omitempty"`
+	}
+)
+
+func routeKey(area, method, path string) string {
+	return area + " " + method + " " + path
+}
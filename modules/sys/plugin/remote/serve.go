@@ -0,0 +1,259 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/component"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+type (
+	// Server is the plugin side of the protocol for plugins written in Go:
+	//
+	//	func main() {
+	//		log.Fatal(remote.Serve(&remote.Server{Routes: ..., Components: ...}))
+	//	}
+	//
+	// Stdout belongs to the protocol when the stdio transport is used, log to stderr.
+	Server struct {
+		Routes     []Route
+		Components []Component
+	}
+
+	Route struct {
+		Area    string
+		Method  string
+		Path    string
+		Name    string
+		Handler http.HandlerFunc
+		// Public serves a backend route without a signed in user, see RouteSpec.
+		Public bool
+	}
+
+	Component struct {
+		Details  component.Details
+		CfgProps component.CfgProps
+		OnRun    func(call *Call) (string, error)
+		OnRender func(call *Call) (string, error)
+		Handlers map[string]func(call *Call) (interface{}, error)
+	}
+
+	// Call is a component call. Props changed on it are sent back to the host.
+	Call struct {
+		Alias   string
+		Props   component.Props
+		Request *http.Request
+	}
+
+	service struct {
+		server     *Server
+		routes     map[string]Route
+		components map[string]Component
+	}
+
+	paramsKey struct{}
+
+	responseWriter struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// Param returns a path parameter of the route handling r.
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
+
+// Serve answers the host on the transport given in the environment until the host goes away.
+func Serve(s *Server) error {
+	svc := &service{
+		server:     s,
+		routes:     make(map[string]Route),
+		components: make(map[string]Component),
+	}
+	for _, r := range s.Routes {
+		svc.routes[routeKey(r.Area, r.Method, r.Path)] = r
+	}
+	for _, c := range s.Components {
+		svc.components[c.Details.Code] = c
+	}
+
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(ServiceName, svc); err != nil {
+		return err
+	}
+
+	switch os.Getenv(EnvTransport) {
+	case TransportUnix:
+		socket := os.Getenv(EnvSocket)
+		if socket == "" {
+			return fmt.Errorf("remote plugin: %s is not set", EnvSocket)
+		}
+		l, err := net.Listen("unix", socket)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+
+		go func() {
+			quit := make(chan os.Signal, 1)
+			signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+			<-quit
+			_ = l.Close()
+		}()
+
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return nil
+				}
+				return err
+			}
+			go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}
+	default:
+		srv.ServeCodec(jsonrpc.NewServerCodec(stdioConn{Reader: os.Stdin, WriteCloser: os.Stdout}))
+		return nil
+	}
+}
+
+func (s *service) Describe(args DescribeArgs, reply *Description) error {
+	reply.ProtocolVersion = ProtocolVersion
+	for _, r := range s.server.Routes {
+		reply.Routes = append(reply.Routes, RouteSpec{Area: r.Area, Method: r.Method, Path: r.Path, Name: r.Name, Public: r.Public})
+	}
+	for _, c := range s.server.Components {
+		spec := ComponentSpec{Details: c.Details, CfgProps: c.CfgProps}
+		for name := range c.Handlers {
+			spec.Handlers = append(spec.Handlers, name)
+		}
+		reply.Components = append(reply.Components, spec)
+	}
+	return nil
+}
+
+func (s *service) ServeHTTP(args HTTPRequest, reply *HTTPResponse) error {
+	r, err := args.toRequest()
+	if err != nil {
+		return err
+	}
+
+	route, ok := s.routes[args.Route]
+	if !ok {
+		return fmt.Errorf("no route %s", args.Route)
+	}
+
+	w := &responseWriter{header: make(http.Header)}
+	route.Handler(w, r)
+	reply.Status, reply.Header, reply.Body = w.statusCode(), w.header, w.body.Bytes()
+	return nil
+}
+
+func (s *service) ComponentRun(args ComponentCall, reply *ComponentResult) error {
+	return s.component(args, reply, func(c Component, call *Call) error {
+		if c.OnRun == nil {
+			return nil
+		}
+		out, err := c.OnRun(call)
+		reply.Output = out
+		return err
+	})
+}
+
+func (s *service) ComponentRender(args ComponentCall, reply *ComponentResult) error {
+	return s.component(args, reply, func(c Component, call *Call) error {
+		if c.OnRender == nil {
+			return nil
+		}
+		out, err := c.OnRender(call)
+		reply.Output = out
+		return err
+	})
+}
+
+func (s *service) ComponentHandler(args ComponentCall, reply *ComponentResult) error {
+	return s.component(args, reply, func(c Component, call *Call) error {
+		h, ok := c.Handlers[args.Handler]
+		if !ok {
+			return fmt.Errorf("component %s has no handler %s", args.Component, args.Handler)
+		}
+		result, err := h(call)
+		reply.Result = result
+		return err
+	})
+}
+
+func (s *service) component(args ComponentCall, reply *ComponentResult, fn func(c Component, call *Call) error) error {
+	c, ok := s.components[args.Component]
+	if !ok {
+		return fmt.Errorf("component %s not found", args.Component)
+	}
+
+	call := &Call{Alias: args.Alias, Props: args.Props}
+	if call.Props == nil {
+		call.Props = make(component.Props)
+	}
+	if args.Request != nil {
+		r, err := args.Request.toRequest()
+		if err != nil {
+			return err
+		}
+		call.Request = r
+	}
+
+	if err := fn(c, call); err != nil {
+		return err
+	}
+	reply.Props = call.Props
+	return nil
+}
+
+func (r HTTPRequest) toRequest() (*http.Request, error) {
+	req, err := http.NewRequest(r.Method, r.URL, io.NopCloser(bytes.NewReader(r.Body)))
+	if err != nil {
+		return nil, err
+	}
+	req.Host = r.Host
+	req.RemoteAddr = r.RemoteAddr
+	if r.Header != nil {
+		req.Header = r.Header
+	}
+	if r.Form != nil {
+		req.Form = url.Values(r.Form)
+	}
+	return req.WithContext(context.WithValue(req.Context(), paramsKey{}, r.Params)), nil
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
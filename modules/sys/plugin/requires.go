@@ -0,0 +1,140 @@
+package plugin
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"sort"
+	"strings"
+)
+
+// resolve checks the plugin requirements and sorts the plugins topologically.
+// Plugins without relation to each other are kept in the order of their codes.
+func (m *Manager) resolve() error {
+	codes := make([]string, 0, len(m.codeMap))
+	for code := range m.codeMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	springVersion, err := semver.NewVersion(spring.Version)
+	if err != nil {
+		return fmt.Errorf("plugin manager: spring version %s: %w", spring.Version, err)
+	}
+
+	for _, code := range codes {
+		details := m.codeMap[code].Plugin().Details()
+
+		if details.Spring != "" {
+			if err = check(springVersion, details.Spring, true); err != nil {
+				return fmt.Errorf("plugin manager: %s requires spring %s, running %s: %w", code, details.Spring, spring.Version, err)
+			}
+		}
+
+		for dep, constraint := range details.Requires {
+			i, ok := m.codeMap[dep]
+			if !ok {
+				return fmt.Errorf("plugin manager: %s requires plugin %s which is not loaded", code, dep)
+			}
+			if constraint == "" || constraint == "*" {
+				continue
+			}
+
+			depVersion := i.Plugin().Details().Version
+			v, err := semver.NewVersion(depVersion)
+			if err != nil {
+				return fmt.Errorf("plugin manager: %s has an invalid version %s: %w", dep, depVersion, err)
+			}
+			if err = check(v, constraint, false); err != nil {
+				return fmt.Errorf("plugin manager: %s requires %s %s, found %s: %w", code, dep, constraint, depVersion, err)
+			}
+		}
+	}
+
+	order := make([]*info, 0, len(codes))
+	state := make(map[string]int, len(codes))
+	var visit func(code string, path []string) error
+	visit = func(code string, path []string) error {
+		switch state[code] {
+		case 1:
+			start := 0
+			for path[start] != code {
+				start++
+			}
+			return fmt.Errorf("plugin manager: dependency cycle %s", strings.Join(append(path[start:], code), " -> "))
+		case 2:
+			return nil
+		}
+
+		state[code] = 1
+		deps := make([]string, 0, len(m.codeMap[code].Plugin().Details().Requires))
+		for dep := range m.codeMap[code].Plugin().Details().Requires {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep, append(path, code)); err != nil {
+				return err
+			}
+		}
+		state[code] = 2
+
+		order = append(order, m.codeMap[code])
+		return nil
+	}
+
+	for _, code := range codes {
+		if err = visit(code, nil); err != nil {
+			return err
+		}
+	}
+
+	m.order = order
+	return nil
+}
+
+// requiredBy returns the codes of the enabled plugins which depend on the plugin.
+func (m *Manager) requiredBy(code string) []string {
+	var codes []string
+	for _, i := range m.order {
+		details := i.Plugin().Details()
+		if _, ok := details.Requires[code]; ok && m.IsEnabled(details.Code) {
+			codes = append(codes, details.Code)
+		}
+	}
+	return codes
+}
+
+func (m *Manager) checkRequired(code string) error {
+	i, ok := m.GetByCode(code)
+	if !ok {
+		return fmt.Errorf("plugin manager: plugin %s not found", code)
+	}
+	for dep := range i.Plugin().Details().Requires {
+		if !m.IsEnabled(dep) {
+			return fmt.Errorf("plugin manager: %s requires plugin %s which is not enabled", code, dep)
+		}
+	}
+	return nil
+}
+
+// check tests v against the constraint. A bare version is a minimum when minimum is set.
+func check(v *semver.Version, constraint string, minimum bool) error {
+	if minimum {
+		if _, err := semver.NewVersion(constraint); err == nil {
+			constraint = ">= " + constraint
+		}
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return err
+	}
+	if ok, errs := c.Validate(v); !ok {
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		return fmt.Errorf("%s does not satisfy %s", v, constraint)
+	}
+	return nil
+}
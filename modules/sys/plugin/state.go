@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"context"
+	"github.com/iagapie/go-spring/modules/sys/postgresdb"
+	"time"
+)
+
+type (
+	// State is the persisted lifecycle state of a plugin.
+	State struct {
+		Code      string    `json:"code" gorm:"primaryKey;size:255"`
+		Version   string    `json:"version" gorm:"size:64"`
+		Installed bool      `json:"installed"`
+		Enabled   bool      `json:"enabled"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	Storage interface {
+		All(ctx context.Context) ([]State, error)
+		Save(ctx context.Context, state State) error
+	}
+
+	Installer interface {
+		Install(ctx context.Context, db *postgresdb.Database) error
+	}
+
+	Uninstaller interface {
+		Uninstall(ctx context.Context, db *postgresdb.Database) error
+	}
+
+	Enabler interface {
+		Enable(ctx context.Context, db *postgresdb.Database) error
+	}
+
+	Disabler interface {
+		Disable(ctx context.Context, db *postgresdb.Database) error
+	}
+)
+
+func (State) TableName() string {
+	return "plugins"
+}
@@ -0,0 +1,28 @@
+package plugin
+
+import "sync"
+
+const staticPrefix = "static:"
+
+var (
+	staticMu      sync.RWMutex
+	staticPlugins []Plugin
+)
+
+// RegisterStatic adds a plugin compiled into the binary. It is meant to be called from the init function
+// of the plugin package, which is then imported by a custom main:
+//
+//	import _ "github.com/author/plugin"
+//
+// Static plugins are loaded by every Manager next to the .so plugins found under the plugins path.
+func RegisterStatic(p Plugin) {
+	staticMu.Lock()
+	defer staticMu.Unlock()
+	staticPlugins = append(staticPlugins, p)
+}
+
+func Static() []Plugin {
+	staticMu.RLock()
+	defer staticMu.RUnlock()
+	return append([]Plugin(nil), staticPlugins...)
+}
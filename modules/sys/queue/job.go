@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type (
+	Job struct {
+		ID          string          `json:"id"`
+		Type        string          `json:"type"`
+		Queue       string          `json:"queue"`
+		Payload     json.RawMessage `json:"payload,omitempty"`
+		Attempts    int             `json:"attempts"`
+		MaxAttempts int             `json:"max_attempts"`
+		Error       string          `json:"error,omitempty"`
+		CreatedAt   time.Time       `json:"created_at"`
+		FailedAt    *time.Time      `json:"failed_at,omitempty"`
+	}
+
+	Handler    func(ctx context.Context, job *Job) error
+	HandlerMap map[string]Handler
+
+	PluginRegisterJobs interface {
+		RegisterJobs() HandlerMap
+	}
+
+	DispatchOption interface {
+		apply(o *dispatchOptions)
+	}
+
+	dispatchOptions struct {
+		queue       string
+		delay       time.Duration
+		maxAttempts int
+	}
+
+	dispatchOption func(o *dispatchOptions)
+)
+
+// Bind decodes the job payload into v.
+func (j *Job) Bind(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+func OnQueue(name string) DispatchOption {
+	return dispatchOption(func(o *dispatchOptions) {
+		o.queue = name
+	})
+}
+
+func WithDelay(delay time.Duration) DispatchOption {
+	return dispatchOption(func(o *dispatchOptions) {
+		o.delay = delay
+	})
+}
+
+func WithMaxAttempts(maxAttempts int) DispatchOption {
+	return dispatchOption(func(o *dispatchOptions) {
+		o.maxAttempts = maxAttempts
+	})
+}
+
+func (fn dispatchOption) apply(o *dispatchOptions) {
+	fn(o)
+}
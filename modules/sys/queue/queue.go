@@ -0,0 +1,369 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/labstack/echo/v4"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	keyPrefix  = "queue:"
+	popTimeout = time.Second
+
+	// a worker refreshes its heartbeat every heartbeatInterval, the jobs it was running are requeued
+	// by the other workers once the heartbeat is missing for heartbeatTTL
+	heartbeatInterval = 10 * time.Second
+	heartbeatTTL      = 3 * heartbeatInterval
+)
+
+var ErrUnknownJob = errors.New("queue: unknown job type")
+
+type Queue struct {
+	mu       sync.RWMutex
+	cfg      config.Queue
+	rdb      redis.UniversalClient
+	log      echo.Logger
+	handlers map[string]Handler
+}
+
+func New(cfg config.Queue, rdb redis.UniversalClient, log echo.Logger) *Queue {
+	return &Queue{
+		cfg:      cfg,
+		rdb:      rdb,
+		log:      log,
+		handlers: make(map[string]Handler),
+	}
+}
+
+func (q *Queue) Register(jobType string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = h
+}
+
+func (q *Queue) Has(jobType string) bool {
+	return q.handler(jobType) != nil
+}
+
+// Dispatch pushes a job of the registered type. The payload is stored as JSON.
+func (q *Queue) Dispatch(ctx context.Context, jobType string, payload interface{}, opts ...DispatchOption) (string, error) {
+	o := &dispatchOptions{
+		queue:       q.cfg.Name,
+		maxAttempts: q.cfg.MaxAttempts,
+	}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("queue: dispatch %s: %w", jobType, err)
+	}
+
+	job := &Job{
+		ID:          uuid.NewString(),
+		Type:        jobType,
+		Queue:       o.queue,
+		Payload:     data,
+		MaxAttempts: o.maxAttempts,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if o.delay > 0 {
+		err = q.schedule(ctx, job, time.Now().Add(o.delay))
+	} else {
+		err = q.push(ctx, readyKey(job.Queue), job)
+	}
+	if err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// Dead returns the jobs that used all their attempts.
+func (q *Queue) Dead(ctx context.Context, queue string) ([]Job, error) {
+	items, err := q.rdb.LRange(ctx, deadKey(queue), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("queue: %w", err)
+	}
+	jobs := make([]Job, 0, len(items))
+	for _, item := range items {
+		var job Job
+		if err = json.Unmarshal([]byte(item), &job); err != nil {
+			return nil, fmt.Errorf("queue: decode: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Retry moves every dead job of the queue back to the ready list with its attempts reset.
+func (q *Queue) Retry(ctx context.Context, queue string) (int, error) {
+	count := 0
+	for {
+		item, err := q.rdb.RPop(ctx, deadKey(queue)).Result()
+		if errors.Is(err, redis.Nil) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("queue: %w", err)
+		}
+
+		var job Job
+		if err = json.Unmarshal([]byte(item), &job); err != nil {
+			return count, fmt.Errorf("queue: decode: %w", err)
+		}
+		job.Attempts = 0
+		job.Error = ""
+		job.FailedAt = nil
+		if err = q.push(ctx, readyKey(queue), &job); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// pop moves the next job to the processing list of the worker, where it stays until ack removes it.
+// The raw job is returned for ack, a job that can't be decoded is dropped.
+func (q *Queue) pop(ctx context.Context, queue, worker string) (*Job, string, error) {
+	if err := q.promote(ctx, queue); err != nil {
+		return nil, "", err
+	}
+
+	raw, err := q.rdb.BRPopLPush(ctx, readyKey(queue), processingKey(queue, worker), popTimeout).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("queue: %w", err)
+	}
+
+	var job Job
+	if err = json.Unmarshal([]byte(raw), &job); err != nil {
+		q.ack(queue, worker, raw)
+		return nil, "", fmt.Errorf("queue: decode: %w", err)
+	}
+	return &job, raw, nil
+}
+
+// ack removes the job from the processing list once it is done, retried or dead.
+func (q *Queue) ack(queue, worker, raw string) {
+	if err := q.rdb.LRem(context.Background(), processingKey(queue, worker), 1, raw).Err(); err != nil {
+		q.log.Error(fmt.Errorf("queue: ack: %w", err))
+	}
+}
+
+// heartbeat registers the worker and tells the other workers it is still running.
+func (q *Queue) heartbeat(ctx context.Context, queue, worker string) error {
+	if err := q.rdb.Set(ctx, heartbeatKey(queue, worker), time.Now().Unix(), heartbeatTTL).Err(); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	if err := q.rdb.SAdd(ctx, workersKey(queue), worker).Err(); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	return nil
+}
+
+// requeue gives the jobs of the workers without a heartbeat, e.g. killed or crashed in the middle of a job,
+// back to the ready list.
+func (q *Queue) requeue(ctx context.Context, queue, self string) error {
+	workers, err := q.rdb.SMembers(ctx, workersKey(queue)).Result()
+	if err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+
+	for _, worker := range workers {
+		if worker == self {
+			continue
+		}
+		alive, err := q.rdb.Exists(ctx, heartbeatKey(queue, worker)).Result()
+		if err != nil {
+			return fmt.Errorf("queue: %w", err)
+		}
+		if alive == 0 {
+			if err = q.release(ctx, queue, worker); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// release moves what is left in the processing list of the worker back to the ready list and unregisters it.
+func (q *Queue) release(ctx context.Context, queue, worker string) error {
+	count := 0
+	for {
+		err := q.rdb.RPopLPush(ctx, processingKey(queue, worker), readyKey(queue)).Err()
+		if errors.Is(err, redis.Nil) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("queue: %w", err)
+		}
+		count++
+	}
+	if count > 0 {
+		q.log.Warnf("queue: %d job(s) of worker %s requeued", count, worker)
+	}
+
+	if err := q.rdb.Del(ctx, heartbeatKey(queue, worker)).Err(); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	if err := q.rdb.SRem(ctx, workersKey(queue), worker).Err(); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) process(ctx context.Context, job *Job) {
+	h := q.handler(job.Type)
+
+	var err error
+	if h == nil {
+		err = fmt.Errorf("%w %s", ErrUnknownJob, job.Type)
+	} else {
+		err = q.run(ctx, h, job)
+	}
+	job.Attempts++
+
+	if err == nil {
+		q.log.Debugf("queue: job %s[%s] done", job.Type, job.ID)
+		return
+	}
+
+	job.Error = err.Error()
+
+	if job.Attempts >= job.MaxAttempts || errors.Is(err, ErrUnknownJob) {
+		now := time.Now().UTC()
+		job.FailedAt = &now
+		q.log.Errorf("queue: job %s[%s] failed after %d attempts: %v", job.Type, job.ID, job.Attempts, err)
+		if err = q.push(context.Background(), deadKey(job.Queue), job); err != nil {
+			q.log.Error(err)
+		}
+		return
+	}
+
+	delay := q.backoff(job.Attempts)
+	q.log.Warnf("queue: job %s[%s] failed, retry %d in %s: %v", job.Type, job.ID, job.Attempts, delay, err)
+	if err = q.schedule(context.Background(), job, time.Now().Add(delay)); err != nil {
+		q.log.Error(err)
+	}
+}
+
+func (q *Queue) run(ctx context.Context, h Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("queue: job %s panic: %v", job.Type, r)
+		}
+	}()
+
+	if q.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.cfg.Timeout)
+		defer cancel()
+	}
+
+	return h(ctx, job)
+}
+
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.cfg.Backoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if q.cfg.MaxBackoff > 0 && delay >= q.cfg.MaxBackoff {
+			return q.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// promote moves the delayed jobs that are due to the ready list.
+func (q *Queue) promote(ctx context.Context, queue string) error {
+	due, err := q.rdb.ZRangeByScore(ctx, delayedKey(queue), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().UnixNano(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+
+	for _, member := range due {
+		removed, err := q.rdb.ZRem(ctx, delayedKey(queue), member).Result()
+		if err != nil {
+			return fmt.Errorf("queue: %w", err)
+		}
+		if removed == 0 {
+			continue // another worker took it
+		}
+		if err = q.rdb.LPush(ctx, readyKey(queue), member).Err(); err != nil {
+			return fmt.Errorf("queue: %w", err)
+		}
+	}
+	return nil
+}
+
+func (q *Queue) schedule(ctx context.Context, job *Job, at time.Time) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	if err = q.rdb.ZAdd(ctx, delayedKey(job.Queue), &redis.Z{
+		Score:  float64(at.UnixNano()),
+		Member: data,
+	}).Err(); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) push(ctx context.Context, key string, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	if err = q.rdb.LPush(ctx, key, data).Err(); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) handler(jobType string) Handler {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if h, ok := q.handlers[jobType]; ok {
+		return h
+	}
+	return nil
+}
+
+func readyKey(queue string) string {
+	return keyPrefix + queue
+}
+
+func delayedKey(queue string) string {
+	return keyPrefix + queue + ":delayed"
+}
+
+func deadKey(queue string) string {
+	return keyPrefix + queue + ":dead"
+}
+
+func processingKey(queue, worker string) string {
+	return keyPrefix + queue + ":processing:" + worker
+}
+
+func heartbeatKey(queue, worker string) string {
+	return keyPrefix + queue + ":worker:" + worker
+}
+
+func workersKey(queue string) string {
+	return keyPrefix + queue + ":workers"
+}
@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type Worker struct {
+	q           *Queue
+	id          string
+	queue       string
+	concurrency int
+}
+
+func (q *Queue) Worker(queue string, concurrency int) *Worker {
+	if queue == "" {
+		queue = q.cfg.Name
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Worker{
+		q:           q,
+		id:          uuid.NewString(),
+		queue:       queue,
+		concurrency: concurrency,
+	}
+}
+
+// Run works the queue until one of the shutdown signals is caught, then waits for the running jobs
+// to finish, for at most the configured shutdown timeout.
+func (w *Worker) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go w.graceful(cancel, syscall.SIGABRT, syscall.SIGQUIT, syscall.SIGHUP, os.Interrupt, syscall.SIGTERM)
+
+	return w.Work(ctx)
+}
+
+// Work starts the workers and blocks until ctx is done and the running jobs are finished.
+// A job stays in the processing list of the worker while it runs, so a crash does not lose it.
+func (w *Worker) Work(ctx context.Context) error {
+	if err := w.q.heartbeat(ctx, w.queue, w.id); err != nil {
+		return err
+	}
+
+	// the heartbeat goes on until the running jobs are finished, not only until ctx is done
+	beatCtx, stopBeat := context.WithCancel(context.Background())
+	defer func() {
+		stopBeat()
+		if err := w.q.release(context.Background(), w.queue, w.id); err != nil {
+			w.q.log.Error(err)
+		}
+	}()
+	go w.beat(beatCtx)
+
+	w.q.log.Infof("queue: %d worker(s) started on %s", w.concurrency, w.queue)
+
+	// jobs keep running after a shutdown signal, they only get cancelled when the shutdown timeout is over
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx, jobCtx)
+		}()
+	}
+
+	<-ctx.Done()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timeout := w.q.cfg.ShutdownTimeout
+	if timeout <= 0 {
+		<-done
+	} else {
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			w.q.log.Warnf("queue: running jobs were cancelled after %s", timeout)
+			cancelJobs()
+			<-done
+		}
+	}
+
+	w.q.log.Info("queue: workers stopped")
+	return nil
+}
+
+func (w *Worker) loop(ctx, jobCtx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		job, raw, err := w.q.pop(ctx, w.queue, w.id)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			w.q.log.Error(err)
+			time.Sleep(popTimeout)
+			continue
+		}
+		if job != nil {
+			w.q.process(jobCtx, job)
+			w.q.ack(w.queue, w.id, raw)
+		}
+	}
+}
+
+// beat refreshes the heartbeat and requeues the jobs of the workers which stopped without releasing them.
+func (w *Worker) beat(ctx context.Context) {
+	if err := w.q.requeue(ctx, w.queue, w.id); err != nil {
+		w.q.log.Error(err)
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.q.heartbeat(ctx, w.queue, w.id); err != nil {
+				w.q.log.Error(err)
+			}
+			if err := w.q.requeue(ctx, w.queue, w.id); err != nil {
+				w.q.log.Error(err)
+			}
+		}
+	}
+}
+
+func (w *Worker) graceful(cancel context.CancelFunc, signals ...os.Signal) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	sig := <-quit
+
+	w.q.log.Infof("Caught signal %s. Shutting down...", sig)
+	cancel()
+}
@@ -0,0 +1,158 @@
+package router
+
+import (
+	"sort"
+	"strings"
+)
+
+type (
+	// linear is the matcher the trie replaced: every rule is resolved in the sorted order until one matches.
+	// It is the reference of the equivalence tests and the baseline of the benchmarks. Sort is stable here
+	// like in router, the old sort.Slice left the order of equal rules unspecified.
+	linear struct {
+		rules []*linearRule
+	}
+
+	linearRule struct {
+		name                string
+		pattern             string
+		segments            []string
+		staticSegmentCount  int
+		dynamicSegmentCount int
+		wildSegmentCount    int
+	}
+)
+
+func (l *linear) Route(name, pattern string) {
+	r := &linearRule{
+		name:     name,
+		pattern:  pattern,
+		segments: SegmentizeUrl(pattern),
+	}
+	for _, segment := range r.segments {
+		if strings.HasPrefix(segment, ":") {
+			r.dynamicSegmentCount++
+			if SegmentIsWildcard(segment) {
+				r.wildSegmentCount++
+			}
+		} else {
+			r.staticSegmentCount++
+		}
+	}
+	l.rules = append(l.rules, r)
+}
+
+func (l *linear) Sort() {
+	sort.SliceStable(l.rules, func(i, j int) bool {
+		if l.rules[i].staticSegmentCount > l.rules[j].staticSegmentCount {
+			return true
+		}
+
+		if l.rules[i].staticSegmentCount == l.rules[j].staticSegmentCount {
+			if l.rules[i].dynamicSegmentCount < l.rules[j].dynamicSegmentCount {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+func (l *linear) Find(url string) (string, Params, bool) {
+	url = NormalizeUrl(url)
+
+	for _, routeRule := range l.rules {
+		if params, ok := routeRule.resolveUrl(url); ok {
+			return routeRule.name, params, true
+		}
+	}
+
+	return "", nil, false
+}
+
+func (r *linearRule) resolveUrl(url string) (Params, bool) {
+	params := make(Params)
+	urlSegments := SegmentizeUrl(url)
+	var wildSegments []string
+
+	if r.wildSegmentCount == 1 {
+		urlSegments, wildSegments = r.captureWildcardSegments(urlSegments)
+	}
+
+	if len(urlSegments) > len(r.segments) {
+		return params, false
+	}
+
+	for index, segment := range r.segments {
+		if strings.HasPrefix(segment, ":") {
+			paramName := ParameterName(segment)
+			params[paramName] = ""
+
+			optional := SegmentIsOptional(segment)
+
+			if optional && index < (len(r.segments)-1) {
+				for i := index + 1; i < len(r.segments); i++ {
+					if !SegmentIsOptional(r.segments[i]) {
+						optional = false
+						break
+					}
+				}
+			}
+
+			urlSegmentExists := len(urlSegments) > index
+
+			if optional && !urlSegmentExists {
+				params[paramName] = SegmentDefaultValue(segment)
+				continue
+			}
+
+			if !optional && !urlSegmentExists {
+				return params, false
+			}
+
+			if re := SegmentRegexp(segment); re != nil {
+				if !re.MatchString(urlSegments[index]) {
+					return params, false
+				}
+			}
+
+			params[paramName] = urlSegments[index]
+
+			if SegmentIsWildcard(segment) && len(wildSegments) > 0 {
+				params[paramName] += RebuildUrl(wildSegments)
+			}
+		} else if len(urlSegments) <= index || !strings.EqualFold(segment, urlSegments[index]) {
+			return params, false
+		}
+	}
+
+	return params, true
+}
+
+func (r *linearRule) captureWildcardSegments(urlSegments []string) ([]string, []string) {
+	newUrlSegments := make([]string, 0)
+	wildSegments := make([]string, 0)
+	segmentDiff := len(urlSegments) - len(r.segments)
+	wildMode := false
+	wildCount := 0
+	jump := false
+
+	for index, urlSegment := range urlSegments {
+		if !jump {
+			if wildMode {
+				if wildCount < segmentDiff {
+					wildSegments = append(wildSegments, urlSegment)
+					wildCount++
+					continue
+				}
+				jump = true
+			} else if SegmentIsWildcard(r.segments[index]) {
+				wildMode = true
+			}
+		}
+
+		newUrlSegments = append(newUrlSegments, urlSegment)
+	}
+
+	return newUrlSegments, wildSegments
+}
@@ -1,9 +1,17 @@
 package router
 
 import (
+	"errors"
 	"fmt"
+	neturl "net/url"
 	"sort"
 	"strings"
+	"sync"
+)
+
+var (
+	ErrRouteNotFound = errors.New("router: route not found")
+	ErrMissingParam  = errors.New("router: missing parameter")
 )
 
 type (
@@ -12,6 +20,10 @@ type (
 	Router interface {
 		Route(name, route string)
 		Match(url string) bool
+		// Find is Match without changing the router, it is safe for concurrent use once the routes are added.
+		Find(url string) (name string, params Params, ok bool)
+		// FindFunc is Find skipping the matching routes which accept rejects, accept can add params.
+		FindFunc(url string, accept func(name string, params Params) bool) (string, Params, bool)
 		Matched() string
 		Params() Params
 		Sort()
@@ -19,43 +31,107 @@ type (
 		URIFromPattern(pattern string, params ...interface{}) string
 		URL(name string, params Params) string
 		URLFromPattern(pattern string, params Params) string
+		// Build returns the URL of the named route, params which are not in its pattern are added as the
+		// query string. With WithStrict a missing required param is an error too, the URL with "default"
+		// is still returned with ErrMissingParam so the caller can fall back to it.
+		Build(name string, params Params) (string, error)
+		// AbsoluteURL is Build prefixed with the base URL set by WithBaseURL.
+		AbsoluteURL(name string, params Params) (string, error)
 	}
 
+	Option func(r *router)
+
 	router struct {
+		mu           sync.RWMutex
 		rules        []*rule
+		tree         *trie
 		matched      *rule
 		params       Params
 		defaultValue string
+		strict       bool
+		baseURL      string
 	}
 )
 
-func New() Router {
-	return &router{
+func WithStrict() Option {
+	return func(r *router) {
+		r.strict = true
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(r *router) {
+		r.baseURL = baseURL
+	}
+}
+
+func New(opts ...Option) Router {
+	r := &router{
 		rules:        make([]*rule, 0),
 		params:       make(Params),
 		defaultValue: "default",
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *router) Route(name, route string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.rules = append(r.rules, newRule(name, route))
+	r.tree = nil
 }
 
 func (r *router) Match(url string) bool {
 	r.matched = nil
-	url = NormalizeUrl(url)
 
-	for _, routeRule := range r.rules {
-		if params, ok := routeRule.resolveUrl(url); ok {
-			r.matched = routeRule
-			r.params = params
-			return true
-		}
+	if routeRule, params := r.find(url, nil); routeRule != nil {
+		r.matched = routeRule
+		r.params = params
+		return true
 	}
 
 	return false
 }
 
+func (r *router) Find(url string) (string, Params, bool) {
+	return r.FindFunc(url, nil)
+}
+
+func (r *router) FindFunc(url string, accept func(name string, params Params) bool) (string, Params, bool) {
+	if routeRule, params := r.find(url, accept); routeRule != nil {
+		return routeRule.name, params, true
+	}
+	return "", nil, false
+}
+
+// find returns the first rule matching the url, the trie is built again after the rules change.
+func (r *router) find(url string, accept func(string, Params) bool) (*rule, Params) {
+	r.mu.RLock()
+	tree := r.tree
+	r.mu.RUnlock()
+
+	if tree == nil {
+		r.mu.Lock()
+		if r.tree == nil {
+			r.tree = newTrie(r.rules)
+		}
+		tree = r.tree
+		r.mu.Unlock()
+	}
+
+	url = NormalizeUrl(url)
+	for _, index := range tree.candidates(SegmentizeUrl(url)) {
+		if params, ok := r.rules[index].resolveUrl(url); ok && (accept == nil || accept(r.rules[index].name, params)) {
+			return r.rules[index], params
+		}
+	}
+
+	return nil, nil
+}
+
 func (r *router) Matched() string {
 	if r.matched == nil {
 		return ""
@@ -68,7 +144,11 @@ func (r *router) Params() Params {
 }
 
 func (r *router) Sort() {
-	sort.Slice(r.rules, func(i, j int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tree = nil
+
+	sort.SliceStable(r.rules, func(i, j int) bool {
 		if r.rules[i].staticSegmentCount > r.rules[j].staticSegmentCount {
 			return true
 		}
@@ -93,7 +173,7 @@ func (r *router) URI(name string, params ...interface{}) string {
 }
 
 func (r *router) URIFromPattern(pattern string, params ...interface{}) string {
-	return r.fromPattern(pattern, func(segment string) (string, bool) {
+	url, _ := r.fromPattern(pattern, func(segment string) (string, bool) {
 		if len(params) > 0 {
 			value := fmt.Sprintf("%v", params[0])
 			params = params[1:]
@@ -101,42 +181,110 @@ func (r *router) URIFromPattern(pattern string, params ...interface{}) string {
 		}
 		return "", false
 	})
+	return url
 }
 
+// URL is Build without the error, it is empty when the route is not found.
 func (r *router) URL(name string, params Params) string {
+	url, _ := r.Build(name, params)
+	return url
+}
+
+func (r *router) URLFromPattern(pattern string, params Params) string {
+	url, _ := r.buildFromPattern(pattern, params)
+	return url
+}
+
+func (r *router) Build(name string, params Params) (string, error) {
 	for _, routeRule := range r.rules {
 		if routeRule.name == name {
-			return r.URLFromPattern(routeRule.pattern, params)
+			return r.buildFromPattern(routeRule.pattern, params)
 		}
 	}
-	return ""
+	return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
 }
 
-func (r *router) URLFromPattern(pattern string, params Params) string {
+func (r *router) AbsoluteURL(name string, params Params) (string, error) {
+	url, err := r.Build(name, params)
+	if len(url) > 0 {
+		url = strings.TrimSuffix(r.baseURL, "/") + url
+	}
+	return url, err
+}
+
+// buildFromPattern fills the pattern from params, the params which are not in the pattern become the query string.
+// The params of the caller are not changed.
+func (r *router) buildFromPattern(pattern string, params Params) (string, error) {
+	normalized := make(Params, len(params))
 	for param, value := range params {
 		if strings.HasPrefix(param, ":") {
-			normalizedParam := param[1:]
-			params[normalizedParam] = value
-			delete(params, param)
+			param = param[1:]
+		} else if _, ok := normalized[param]; ok {
+			continue
 		}
+		normalized[param] = value
 	}
+	params = normalized
 
-	return r.fromPattern(pattern, func(segment string) (string, bool) {
+	used := make(map[string]bool, len(params))
+	url, missing := r.fromPattern(pattern, func(segment string) (string, bool) {
 		paramName := ParameterName(segment)
+		used[paramName] = true
 		defaultValue := SegmentDefaultValue(segment)
 		if value, ok := params[paramName]; ok && len(value) > 0 && value != defaultValue {
-			return value, true
+			return escapePath(segment, value), true
 		}
 		return "", false
 	})
+	query := make(neturl.Values)
+	for param, value := range params {
+		if !used[param] && len(value) > 0 {
+			query.Set(param, value)
+		}
+	}
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+	if len(missing) > 0 && r.strict {
+		return url, fmt.Errorf("%w %s for %s", ErrMissingParam, strings.Join(missing, ", "), pattern)
+	}
+	return url, nil
+}
+
+// escapePath escapes the value of a param segment, the slashes of a wildcard value separate segments.
+func escapePath(segment, value string) string {
+	if !SegmentIsWildcard(segment) {
+		return neturl.PathEscape(value)
+	}
+	parts := strings.Split(value, "/")
+	for i, part := range parts {
+		parts[i] = neturl.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
 }
 
-func (r *router) fromPattern(pattern string, fn func(string) (string, bool)) string {
+// fromPattern builds the URL and returns the names of the required params which got the default value.
+func (r *router) fromPattern(pattern string, fn func(string) (string, bool)) (string, []string) {
 	url := make([]string, 0)
 	lastPopulatedIndex := 0
+	var missing []string
+	missingIndex := make(map[string]int)
 
 	for index, segment := range SegmentizeUrl(pattern) {
-		if strings.HasPrefix(segment, ":") {
+		if parsed := parseSegment(segment); parsed.composite {
+			b := new(strings.Builder)
+			for _, t := range tokenize(segment) {
+				if t.param == "" {
+					b.WriteString(t.literal)
+				} else if value, ok := fn(":" + t.param); ok {
+					b.WriteString(value)
+				} else {
+					b.WriteString(r.defaultValue)
+					missingIndex[t.param] = index
+				}
+			}
+			url = append(url, b.String())
+		} else if strings.HasPrefix(segment, ":") {
 			if value, ok := fn(segment); ok {
 				url = append(url, value)
 			} else if SegmentIsOptional(segment) {
@@ -144,10 +292,12 @@ func (r *router) fromPattern(pattern string, fn func(string) (string, bool)) str
 					url = append(url, defaultValue)
 				} else {
 					url = append(url, r.defaultValue)
+					missingIndex[ParameterName(segment)] = index
 				}
 				continue
 			} else {
 				url = append(url, r.defaultValue)
+				missingIndex[ParameterName(segment)] = index
 			}
 		} else {
 			url = append(url, segment)
@@ -160,5 +310,13 @@ func (r *router) fromPattern(pattern string, fn func(string) (string, bool)) str
 		url = url[:lastPopulatedIndex+1]
 	}
 
-	return RebuildUrl(url)
+	// optional params after the last populated segment are cut off, they are not missing
+	for name, index := range missingIndex {
+		if index <= lastPopulatedIndex && index < len(url) {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	return RebuildUrl(url), missing
 }
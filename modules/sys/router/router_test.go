@@ -0,0 +1,217 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	// urlWords are the static segments and the URL segments of the random tests, with case variants
+	// and non-ASCII runes for the case folding of the trie
+	urlWords = []string{"blog", "Blog", "BLOG", "post", "news", "a", "b", "42", "7", "x-y", "ǅ", "ǆ", "straße", "STRASSE", "k", "K"}
+
+	paramSegments = []string{":slug", ":id|^[0-9]+$", ":page?", ":page?1", ":lang?en|^[a-z]{2}$", ":path*", ":rest?*"}
+)
+
+// randomPattern returns a pattern of 0 to 4 segments, parameter names are unique within it.
+func randomPattern(rnd *rand.Rand) string {
+	segments := make([]string, rnd.Intn(5))
+	for i := range segments {
+		if rnd.Intn(2) == 0 {
+			segments[i] = urlWords[rnd.Intn(len(urlWords))]
+			continue
+		}
+		p := paramSegments[rnd.Intn(len(paramSegments))]
+		name := ParameterName(p)
+		segments[i] = strings.Replace(p, name, fmt.Sprintf("%s%d", name, i), 1)
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func randomURL(rnd *rand.Rand) string {
+	segments := make([]string, rnd.Intn(7))
+	for i := range segments {
+		segments[i] = urlWords[rnd.Intn(len(urlWords))]
+	}
+	url := "/" + strings.Join(segments, "/")
+	if rnd.Intn(4) == 0 {
+		url += "/"
+	}
+	return url
+}
+
+// equivalent routes the patterns in both routers and compares what they find for the url.
+func equivalent(t *testing.T, patterns []string, urls []string) {
+	t.Helper()
+
+	r, l := New(), &linear{}
+	for i, pattern := range patterns {
+		name := fmt.Sprintf("r%d", i)
+		r.Route(name, pattern)
+		l.Route(name, pattern)
+	}
+	r.Sort()
+	l.Sort()
+
+	for _, url := range urls {
+		name, params, ok := r.Find(url)
+		wantName, wantParams, wantOk := l.Find(url)
+		if name != wantName || ok != wantOk || !reflect.DeepEqual(params, wantParams) {
+			t.Fatalf("routes %q, Find(%q) = %q %v %v, the linear scan found %q %v %v",
+				patterns, url, name, params, ok, wantName, wantParams, wantOk)
+		}
+	}
+}
+
+func TestFindEquivalence(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		patterns := make([]string, 1+rnd.Intn(12))
+		for j := range patterns {
+			patterns[j] = randomPattern(rnd)
+		}
+		urls := make([]string, 50)
+		for j := range urls {
+			urls[j] = randomURL(rnd)
+		}
+		equivalent(t, patterns, urls)
+	}
+}
+
+func TestFindPrecedence(t *testing.T) {
+	r := New()
+	r.Route("slug", "/blog/:slug")
+	r.Route("page", "/blog/:slug/:page?1")
+	r.Route("latest", "/blog/latest")
+	r.Route("any", "/:path*")
+	r.Sort()
+
+	tests := []struct {
+		url    string
+		name   string
+		params Params
+	}{
+		{"/blog/latest", "latest", Params{}},
+		{"/BLOG/Latest/", "latest", Params{}},
+		{"/blog/hello", "slug", Params{"slug": "hello"}},
+		{"/blog/hello/2", "page", Params{"slug": "hello", "page": "2"}},
+		{"/blog/hello/2/3", "any", Params{"path": "blog/hello/2/3"}},
+	}
+	for _, tt := range tests {
+		name, params, ok := r.Find(tt.url)
+		if !ok || name != tt.name || !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("Find(%q) = %q %v %v, want %q %v", tt.url, name, params, ok, tt.name, tt.params)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r := New(WithStrict(), WithBaseURL("https://example.com/"))
+	r.Route("post", "/blog/:slug/:page?1")
+	r.Route("archive", "/archive/:year-:month")
+	r.Route("files", "/files/:path*")
+	r.Sort()
+
+	tests := []struct {
+		name    string
+		route   string
+		params  Params
+		url     string
+		missing bool
+	}{
+		{"escaped", "post", Params{"slug": "a/b?c"}, "/blog/a%2Fb%3Fc", false},
+		{"default omitted", "post", Params{"slug": "hello", "page": "1"}, "/blog/hello", false},
+		{"optional", "post", Params{"slug": "hello", "page": "2"}, "/blog/hello/2", false},
+		{"prefixed param", "post", Params{":slug": "hello"}, "/blog/hello", false},
+		{"query", "post", Params{"slug": "hello", "q": "a b&c", "empty": ""}, "/blog/hello?q=a+b%26c", false},
+		{"composite escaped", "archive", Params{"year": "2021", "month": "0/1"}, "/archive/2021-0%2F1", false},
+		{"wildcard keeps slashes", "files", Params{"path": "a b/c"}, "/files/a%20b/c", false},
+		{"missing", "post", Params{"q": "x"}, "/blog/default?q=x", true},
+		{"missing in composite", "archive", Params{"year": "2021"}, "/archive/2021-default", true},
+	}
+	for _, tt := range tests {
+		params := make(Params)
+		for k, v := range tt.params {
+			params[k] = v
+		}
+
+		url, err := r.Build(tt.route, params)
+		if url != tt.url {
+			t.Errorf("%s: Build = %q, want %q", tt.name, url, tt.url)
+		}
+		if missing := errors.Is(err, ErrMissingParam); missing != tt.missing || (!tt.missing && err != nil) {
+			t.Errorf("%s: Build error = %v, want missing %v", tt.name, err, tt.missing)
+		}
+		if !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("%s: Build changed the params to %v", tt.name, params)
+		}
+	}
+
+	if _, err := r.Build("nope", nil); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("Build of an unknown route: err = %v, want %v", err, ErrRouteNotFound)
+	}
+
+	url, err := r.AbsoluteURL("post", Params{"slug": "hello"})
+	if url != "https://example.com/blog/hello" || err != nil {
+		t.Errorf("AbsoluteURL = %q %v, want https://example.com/blog/hello", url, err)
+	}
+	url, err = r.AbsoluteURL("post", nil)
+	if url != "https://example.com/blog/default" || !errors.Is(err, ErrMissingParam) {
+		t.Errorf("AbsoluteURL without params = %q %v, want the default URL and %v", url, err, ErrMissingParam)
+	}
+
+	lenient := New()
+	lenient.Route("post", "/blog/:slug")
+	if url, err := lenient.Build("post", nil); url != "/blog/default" || err != nil {
+		t.Errorf("Build without WithStrict = %q %v, want /blog/default and no error", url, err)
+	}
+}
+
+// pages routes n pages like a large theme: static pages, one dynamic page per section and a catch-all.
+func pages(n int, route func(name, pattern string)) []string {
+	urls := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0, 1:
+			route(fmt.Sprintf("page-%d", i), fmt.Sprintf("/page-%d", i))
+			urls = append(urls, fmt.Sprintf("/page-%d", i))
+		case 2:
+			route(fmt.Sprintf("post-%d", i), fmt.Sprintf("/section-%d/:slug/:page?1", i))
+			urls = append(urls, fmt.Sprintf("/section-%d/post/2", i))
+		default:
+			route(fmt.Sprintf("item-%d", i), fmt.Sprintf("/shop/category-%d/:id|^[0-9]+$", i))
+			urls = append(urls, fmt.Sprintf("/shop/category-%d/42", i))
+		}
+	}
+	route("not-found", "/:path*")
+	return append(urls, "/missing/page")
+}
+
+func BenchmarkFind(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		r := New()
+		urls := pages(n, r.Route)
+		r.Sort()
+		r.Find("/")
+
+		b.Run(fmt.Sprintf("trie/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				r.Find(urls[i%len(urls)])
+			}
+		})
+
+		l := &linear{}
+		pages(n, l.Route)
+		l.Sort()
+
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				l.Find(urls[i%len(urls)])
+			}
+		})
+	}
+}
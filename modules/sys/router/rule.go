@@ -1,33 +1,33 @@
 package router
 
-import "strings"
-
 type rule struct {
 	name                string
 	pattern             string
 	staticURL           string
-	segments            []string
+	segments            []*segment
 	staticSegmentCount  int
 	dynamicSegmentCount int
 	wildSegmentCount    int
 }
 
+// newRule parses the pattern once, the regular expressions of its segments are compiled here.
 func newRule(name, pattern string) *rule {
 	r := &rule{
-		name:     name,
-		pattern:  pattern,
-		segments: SegmentizeUrl(pattern),
+		name:    name,
+		pattern: pattern,
 	}
 	staticSegments := make([]string, 0)
-	for _, segment := range r.segments {
-		if strings.HasPrefix(segment, ":") {
-			r.dynamicSegmentCount++
-			if SegmentIsWildcard(segment) {
-				r.wildSegmentCount++
-			}
-		} else {
-			staticSegments = append(staticSegments, segment)
+	for _, raw := range SegmentizeUrl(pattern) {
+		s := parseSegment(raw)
+		r.segments = append(r.segments, s)
+		if s.static {
+			staticSegments = append(staticSegments, raw)
 			r.staticSegmentCount++
+			continue
+		}
+		r.dynamicSegmentCount++
+		if s.wildcard {
+			r.wildSegmentCount++
 		}
 	}
 	r.staticURL = RebuildUrl(staticSegments)
@@ -48,46 +48,44 @@ func (r *rule) resolveUrl(url string) (Params, bool) {
 	}
 
 	for index, segment := range r.segments {
-		if strings.HasPrefix(segment, ":") {
-			paramName := ParameterName(segment)
-			params[paramName] = ""
-
-			optional := SegmentIsOptional(segment)
-
-			if optional && index < (len(r.segments)-1) {
-				for i := index + 1; i < len(r.segments); i++ {
-					if !SegmentIsOptional(r.segments[i]) {
-						optional = false
-						break
-					}
-				}
-			}
+		urlSegmentExists := len(urlSegments) > index
 
-			urlSegmentExists := len(urlSegments) > index
-
-			if optional && !urlSegmentExists {
-				params[paramName] = SegmentDefaultValue(segment)
-				continue
-			}
-
-			if !optional && !urlSegmentExists {
+		if segment.static || segment.composite {
+			if !urlSegmentExists || !segment.match(urlSegments[index], params) {
 				return params, false
 			}
+			continue
+		}
+
+		params[segment.name] = ""
 
-			if re := SegmentRegexp(segment); re != nil {
-				if !re.MatchString(urlSegments[index]) {
-					return params, false
+		optional := segment.optional
+
+		if optional && index < (len(r.segments)-1) {
+			for i := index + 1; i < len(r.segments); i++ {
+				if !r.segments[i].optional {
+					optional = false
+					break
 				}
 			}
+		}
 
-			params[paramName] = urlSegments[index]
+		if optional && !urlSegmentExists {
+			params[segment.name] = segment.def
+			continue
+		}
 
-			if SegmentIsWildcard(segment) && len(wildSegments) > 0 {
-				params[paramName] += RebuildUrl(wildSegments)
-			}
-		} else if len(urlSegments) <= index || !strings.EqualFold(segment, urlSegments[index]) {
+		if !optional && !urlSegmentExists {
 			return params, false
 		}
+
+		if !segment.match(urlSegments[index], params) {
+			return params, false
+		}
+
+		if segment.wildcard && len(wildSegments) > 0 {
+			params[segment.name] += RebuildUrl(wildSegments)
+		}
 	}
 
 	return params, true
@@ -110,7 +108,7 @@ func (r *rule) captureWildcardSegments(urlSegments []string) ([]string, []string
 					continue
 				}
 				jump = true
-			} else if SegmentIsWildcard(r.segments[index]) {
+			} else if r.segments[index].wildcard {
 				wildMode = true
 			}
 		}
@@ -0,0 +1,214 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+)
+
+// constraints are the built-in types of :name<type>, any other constraint is a regular expression.
+var constraints = map[string]string{
+	"int":  `[0-9]+`,
+	"slug": `[a-z0-9]+(?:-[a-z0-9]+)*`,
+	"uuid": `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+var never = regexp.MustCompile(`^\b\B$`)
+
+type (
+	// segment is a compiled segment of a rule pattern, one of:
+	// static "blog", param ":slug?default|regex" or ":id<int>", composite ":year-:month" or "feed.:format<xml|json>".
+	segment struct {
+		raw       string
+		static    bool
+		composite bool
+		name      string
+		def       string
+		optional  bool
+		wildcard  bool
+		re        *regexp.Regexp
+		names     []string
+	}
+
+	// token is a literal or a parameter of a composite segment.
+	token struct {
+		literal    string
+		param      string
+		constraint string
+	}
+)
+
+func parseSegment(raw string) *segment {
+	s := &segment{raw: raw}
+
+	tokens := tokenize(raw)
+	params := 0
+	for _, t := range tokens {
+		if t.param != "" {
+			params++
+		}
+	}
+
+	switch {
+	case params == 0 && !strings.HasPrefix(raw, ":"):
+		s.static = true
+	case strings.HasPrefix(raw, ":") && !isCompositeParam(raw, tokens, params):
+		s.name = ParameterName(raw)
+		s.def = SegmentDefaultValue(raw)
+		s.optional = SegmentIsOptional(raw)
+		s.wildcard = SegmentIsWildcard(raw)
+		s.re = SegmentRegexp(raw)
+	default:
+		s.composite = true
+		b := new(strings.Builder)
+		b.WriteString("^")
+		for _, t := range tokens {
+			if t.param == "" {
+				b.WriteString("(?i:" + regexp.QuoteMeta(t.literal) + ")")
+				continue
+			}
+			expr := `[^/]+?`
+			if t.constraint != "" {
+				expr = constraintExpr(t.constraint)
+			}
+			b.WriteString("(" + expr + ")")
+			s.names = append(s.names, t.param)
+		}
+		b.WriteString("$")
+		if re, err := regexp.Compile(b.String()); err == nil {
+			s.re = re
+		} else {
+			s.re = never
+		}
+	}
+	return s
+}
+
+// match checks the URL segment and stores the captured params.
+func (s *segment) match(value string, params Params) bool {
+	switch {
+	case s.static:
+		return strings.EqualFold(s.raw, value)
+	case s.composite:
+		m := s.re.FindStringSubmatch(value)
+		if m == nil {
+			return false
+		}
+		for i, name := range s.names {
+			params[name] = m[i+1]
+		}
+		return true
+	}
+	if s.re != nil && !s.re.MatchString(value) {
+		return false
+	}
+	params[s.name] = value
+	return true
+}
+
+// isCompositeParam tells ":year-:month" from the older single parameter forms, e.g. ":post-id" or ":name|^a:b$".
+func isCompositeParam(raw string, tokens []token, params int) bool {
+	if params < 2 {
+		return false
+	}
+	plain, _ := splitConstraint(raw)
+	name := paramNameEnd(plain, 1)
+	return name < len(plain) && strings.IndexByte("?|*", plain[name]) == -1
+}
+
+// tokenize splits a segment into literals and :name or :name<constraint> parameters.
+func tokenize(raw string) []token {
+	var (
+		tokens  []token
+		literal strings.Builder
+	)
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != ':' || i+1 >= len(raw) || !isNameStart(raw[i+1]) {
+			literal.WriteByte(raw[i])
+			continue
+		}
+
+		if literal.Len() > 0 {
+			tokens = append(tokens, token{literal: literal.String()})
+			literal.Reset()
+		}
+
+		end := paramNameEnd(raw, i+1)
+		t := token{param: raw[i+1 : end]}
+		if end < len(raw) && raw[end] == '<' {
+			if closing := constraintEnd(raw, end); closing != -1 {
+				t.constraint = raw[end+1 : closing]
+				end = closing + 1
+			}
+		}
+		tokens = append(tokens, t)
+		i = end - 1
+	}
+	if literal.Len() > 0 {
+		tokens = append(tokens, token{literal: literal.String()})
+	}
+	return tokens
+}
+
+// splitConstraint removes the <constraint> following the parameter name: ":page<int>?1" gives ":page?1" and "int".
+func splitConstraint(segment string) (string, string) {
+	if !strings.HasPrefix(segment, ":") {
+		return segment, ""
+	}
+	end := paramNameEnd(segment, 1)
+	if end >= len(segment) || segment[end] != '<' {
+		return segment, ""
+	}
+	closing := constraintEnd(segment, end)
+	if closing == -1 {
+		return segment, ""
+	}
+	return segment[:end] + segment[closing+1:], segment[end+1 : closing]
+}
+
+// constraintEnd returns the index of the '>' closing the '<' at start, nested pairs such as (?P<name>...) included.
+func constraintEnd(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func paramNameEnd(s string, start int) int {
+	i := start
+	for i < len(s) && (isNameStart(s[i]) || ('0' <= s[i] && s[i] <= '9')) {
+		i++
+	}
+	return i
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func constraintExpr(constraint string) string {
+	if expr, ok := constraints[constraint]; ok {
+		return expr
+	}
+	return "(?:" + constraint + ")"
+}
+
+// compileConstraint anchors the constraint, it has to match the whole segment unlike |regex.
+// An invalid constraint never matches.
+func compileConstraint(constraint string) *regexp.Regexp {
+	re, err := regexp.Compile("^" + constraintExpr(constraint) + "$")
+	if err != nil {
+		return never
+	}
+	return re
+}
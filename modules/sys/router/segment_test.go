@@ -0,0 +1,92 @@
+package router
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSegment(t *testing.T) {
+	tests := []struct {
+		raw       string
+		static    bool
+		composite bool
+		name      string
+		names     []string
+	}{
+		{raw: "blog", static: true},
+		{raw: "feed.xml", static: true},
+		{raw: ":id<int>", name: "id"},
+		{raw: ":page<int>?1", name: "page"},
+		{raw: ":post-id", name: "post-id"},
+		{raw: ":name|^a:b$", name: "name"},
+		{raw: ":year-:month", composite: true, names: []string{"year", "month"}},
+		{raw: ":year<int>-:month<int>", composite: true, names: []string{"year", "month"}},
+		{raw: "feed.:format<xml|json>", composite: true, names: []string{"format"}},
+		{raw: "v:major.:minor", composite: true, names: []string{"major", "minor"}},
+	}
+
+	for _, tt := range tests {
+		s := parseSegment(tt.raw)
+		if s.static != tt.static || s.composite != tt.composite || s.name != tt.name || !reflect.DeepEqual(s.names, tt.names) {
+			t.Errorf("parseSegment(%q) = static %v, composite %v, name %q, names %v, want %v, %v, %q, %v",
+				tt.raw, s.static, s.composite, s.name, s.names, tt.static, tt.composite, tt.name, tt.names)
+		}
+	}
+}
+
+// TestFindConstraints covers the typed constraints, several params in one segment and extensions.
+// Static text matches case-insensitively, parameter values and their constraints are case-sensitive.
+func TestFindConstraints(t *testing.T) {
+	r := New()
+	r.Route("post", "/post/:id<int>/:slug<slug>")
+	r.Route("item", "/item/:id<uuid>")
+	r.Route("archive", "/archive/:year<int>-:month")
+	r.Route("feed", "/feed.:format<xml|json>")
+	r.Route("page", "/page/:n<int>?1")
+	r.Route("code", "/code/:code<[A-Z]{3}>")
+	r.Sort()
+
+	tests := []struct {
+		url    string
+		name   string
+		params Params
+		build  string
+	}{
+		{"/post/42/hello-world", "post", Params{"id": "42", "slug": "hello-world"}, "/post/42/hello-world"},
+		{"/post/x/hello", "", nil, ""},
+		{"/post/42/Hello", "", nil, ""},
+		{"/post/42/hello--world", "", nil, ""},
+		{"/item/123e4567-E89B-12d3-a456-426614174000", "item", Params{"id": "123e4567-E89B-12d3-a456-426614174000"}, "/item/123e4567-E89B-12d3-a456-426614174000"},
+		{"/item/123", "", nil, ""},
+		{"/archive/2021-05", "archive", Params{"year": "2021", "month": "05"}, "/archive/2021-05"},
+		{"/archive/2021-05-01", "archive", Params{"year": "2021", "month": "05-01"}, "/archive/2021-05-01"},
+		{"/archive/20x1-05", "", nil, ""},
+		{"/archive/2021", "", nil, ""},
+		{"/feed.xml", "feed", Params{"format": "xml"}, "/feed.xml"},
+		{"/feed.json", "feed", Params{"format": "json"}, "/feed.json"},
+		{"/FEED.xml", "feed", Params{"format": "xml"}, "/feed.xml"},
+		{"/FEED.XML", "", nil, ""},
+		{"/feed.rss", "", nil, ""},
+		{"/feed.", "", nil, ""},
+		{"/page", "page", Params{"n": "1"}, "/page"},
+		{"/page/3", "page", Params{"n": "3"}, "/page/3"},
+		{"/page/x", "", nil, ""},
+		{"/code/ABC", "code", Params{"code": "ABC"}, "/code/ABC"},
+		{"/code/ABCD", "", nil, ""},
+		{"/code/abc", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		name, params, ok := r.Find(tt.url)
+		if name != tt.name || ok != (tt.name != "") || !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("Find(%q) = %q %v %v, want %q %v", tt.url, name, params, ok, tt.name, tt.params)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if url, err := r.Build(name, params); url != tt.build || err != nil {
+			t.Errorf("Build(%q, %v) = %q %v, want %q", name, params, url, err, tt.build)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// node is a level of the trie, static segments are keyed by their case folded value. Every other
+// segment goes to the dynamic child, rules reaching a node with only optional segments left end there.
+type node struct {
+	static  map[string]*node
+	dynamic *node
+	ends    []int
+}
+
+// trie indexes the rules by their segments. It only narrows the rules down, the candidates are checked
+// with resolveUrl in the order of the rules, so the first matching rule is the same as with a linear scan.
+type trie struct {
+	root *node
+	// wild are the rules with a wildcard segment, they can match any number of segments and are always checked
+	wild []int
+}
+
+func newTrie(rules []*rule) *trie {
+	t := &trie{root: &node{}}
+	for i, r := range rules {
+		if r.wildSegmentCount > 0 {
+			t.wild = append(t.wild, i)
+			continue
+		}
+		t.insert(i, r)
+	}
+	return t
+}
+
+func (t *trie) insert(index int, r *rule) {
+	// the URL can stop before the tail of optional segments
+	tail := len(r.segments)
+	for tail > 0 && r.segments[tail-1].optional {
+		tail--
+	}
+
+	n := t.root
+	for depth, s := range r.segments {
+		if depth >= tail {
+			n.ends = append(n.ends, index)
+		}
+		if s.static {
+			key := foldKey(s.raw)
+			if n.static == nil {
+				n.static = make(map[string]*node)
+			}
+			if n.static[key] == nil {
+				n.static[key] = &node{}
+			}
+			n = n.static[key]
+		} else {
+			if n.dynamic == nil {
+				n.dynamic = &node{}
+			}
+			n = n.dynamic
+		}
+	}
+	n.ends = append(n.ends, index)
+}
+
+// candidates returns the indexes of the rules which can match the segments, in ascending order.
+func (t *trie) candidates(segments []string) []int {
+	found := append([]int(nil), t.wild...)
+	t.collect(t.root, segments, &found)
+	sort.Ints(found)
+	return found
+}
+
+func (t *trie) collect(n *node, segments []string, found *[]int) {
+	if len(segments) == 0 {
+		*found = append(*found, n.ends...)
+		return
+	}
+	if child, ok := n.static[foldKey(segments[0])]; ok {
+		t.collect(child, segments[1:], found)
+	}
+	if n.dynamic != nil {
+		t.collect(n.dynamic, segments[1:], found)
+	}
+}
+
+// foldKey maps every rune to the smallest rune folding to it, two strings have the same key exactly
+// when strings.EqualFold reports them equal.
+func foldKey(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		b := []byte(s)
+		for i, c := range b {
+			if 'a' <= c && c <= 'z' {
+				b[i] = c - 'a' + 'A'
+			}
+		}
+		// 'K' and 'S' fold to non-ASCII runes too, but those are greater
+		return string(b)
+	}
+
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		runes = append(runes, min)
+	}
+	return string(runes)
+}
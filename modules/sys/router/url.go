@@ -33,6 +33,7 @@ func RebuildUrl(segments []string) string {
 }
 
 func SegmentDefaultValue(segment string) string {
+	segment, _ = splitConstraint(segment)
 	optMarkerPos := strings.IndexRune(segment, '?')
 	if optMarkerPos == -1 {
 		return ""
@@ -48,10 +49,12 @@ func SegmentDefaultValue(segment string) string {
 }
 
 func SegmentIsWildcard(segment string) bool {
+	segment, _ = splitConstraint(segment)
 	return strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "*")
 }
 
 func ParameterName(segment string) string {
+	segment, _ = splitConstraint(segment)
 	name := segment[1:]
 
 	optMarkerPos := strings.IndexRune(name, '?')
@@ -84,6 +87,7 @@ func ParameterName(segment string) string {
 }
 
 func SegmentIsOptional(segment string) bool {
+	segment, _ = splitConstraint(segment)
 	name := segment[1:]
 
 	optMarkerPos := strings.IndexRune(name, '?')
@@ -99,7 +103,12 @@ func SegmentIsOptional(segment string) bool {
 	return optMarkerPos < regexMarkerPos
 }
 
+// SegmentRegexp compiles the constraint of the segment, rules compile it once when they are created.
 func SegmentRegexp(segment string) *regexp.Regexp {
+	segment, constraint := splitConstraint(segment)
+	if constraint != "" {
+		return compileConstraint(constraint)
+	}
 	if pos := strings.IndexRune(segment, '|'); pos != -1 {
 		if r, err := regexp.Compile(segment[pos+1:]); err == nil {
 			return r
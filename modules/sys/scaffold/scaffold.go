@@ -0,0 +1,289 @@
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/iagapie/go-spring/modules/sys/helper"
+	"github.com/iagapie/go-spring/modules/sys/spring"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const marker = "// create:component"
+
+var (
+	//go:embed templates/*.tmpl
+	files     embed.FS
+	templates = template.Must(template.New("").Delims("[[", "]]").ParseFS(files, "templates/*.tmpl"))
+
+	codeRegex      = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	componentRegex = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	themeRegex     = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+	ErrExists = errors.New("scaffold: file already exists")
+)
+
+type (
+	// Options tell where the project is. Module is the Go module path of the project.
+	Options struct {
+		Module      string
+		PluginsPath string
+		Makefile    string
+	}
+
+	data struct {
+		Module         string
+		Package        string
+		Dir            string
+		Author         string
+		Name           string
+		Code           string
+		Title          string
+		SpringVersion  string
+		Component      string
+		ComponentTitle string
+		Type           string
+		Alias          string
+		Parent         string
+	}
+)
+
+// ParseCode splits a plugin code in the author.name format.
+func ParseCode(code string) (string, string, error) {
+	parts := strings.Split(code, ".")
+	if len(parts) != 2 || !codeRegex.MatchString(parts[0]) || !codeRegex.MatchString(parts[1]) {
+		return "", "", fmt.Errorf("scaffold: plugin must be in the author.name format using a-z, 0-9 and _, got %s", code)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ModulePath reads the module path from the go.mod file in dir.
+func ModulePath(dir string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("scaffold: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(line[len("module "):]), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("scaffold: module path not found in go.mod")
+}
+
+// CreatePlugin writes the skeleton of a new plugin and adds its build target to the Makefile.
+func CreatePlugin(opts Options, author, name string) ([]string, error) {
+	d := newData(opts, author, name)
+
+	if _, err := os.Stat(d.Dir); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrExists, d.Dir)
+	}
+
+	created, err := write(d, map[string]string{
+		filepath.Join(d.Dir, d.Code+".go"):                  "plugin.go.tmpl",
+		filepath.Join(d.Dir, d.Code+"_test.go"):             "plugin_test.go.tmpl",
+		filepath.Join(d.Dir, "components", "components.go"): "components.go.tmpl",
+	})
+	if err != nil {
+		return created, err
+	}
+
+	if opts.Makefile != "" {
+		if err = addMakeTarget(opts.Makefile, d); err != nil {
+			return created, err
+		}
+		created = append(created, opts.Makefile)
+	}
+
+	return created, nil
+}
+
+// CreateComponent adds a component to an existing plugin and registers it in components/components.go.
+func CreateComponent(opts Options, author, name, component string) ([]string, error) {
+	if !componentRegex.MatchString(component) {
+		return nil, fmt.Errorf("scaffold: component name must be CamelCase, got %s", component)
+	}
+
+	d := newData(opts, author, name)
+	d.Component = component
+	d.Type = string(unicode.ToLower(rune(component[0]))) + component[1:]
+	d.Alias = snake(component)
+	d.ComponentTitle = strings.Title(strings.ReplaceAll(d.Alias, "_", " "))
+
+	registry := filepath.Join(d.Dir, "components", "components.go")
+	if _, err := os.Stat(registry); err != nil {
+		return nil, fmt.Errorf("scaffold: plugin %s.%s not found, run create:plugin first: %w", author, name, err)
+	}
+
+	created, err := write(d, map[string]string{
+		filepath.Join(d.Dir, "components", d.Alias+".go"):           "component.go.tmpl",
+		filepath.Join(d.Dir, "components", d.Alias+"_test.go"):      "component_test.go.tmpl",
+		filepath.Join(d.Dir, "components", d.Alias, "default.html"): "default.html.tmpl",
+	})
+	if err != nil {
+		return created, err
+	}
+
+	b, err := os.ReadFile(registry)
+	if err != nil {
+		return created, fmt.Errorf("scaffold: %w", err)
+	}
+	if !bytes.Contains(b, []byte(marker)) {
+		return created, fmt.Errorf("scaffold: %s has no %q marker, register New%s by hand", registry, marker, component)
+	}
+	b = bytes.Replace(b, []byte(marker), []byte(fmt.Sprintf("%q: New%s,\n\t%s", d.Alias, component, marker)), 1)
+	if err = writeFile(registry, b, true); err != nil {
+		return created, err
+	}
+
+	return append(created, registry), nil
+}
+
+// CreateTheme writes a minimal theme: theme.yml, the default layout and the home, 404 and error pages.
+// A child theme only gets theme.yml, everything else is inherited from parent.
+func CreateTheme(themesPath, name, author, parent string) ([]string, error) {
+	if !themeRegex.MatchString(name) {
+		return nil, fmt.Errorf("scaffold: theme name must use a-z, 0-9, _ and -, got %s", name)
+	}
+
+	dir := filepath.Join(themesPath, name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrExists, dir)
+	}
+
+	d := data{
+		Dir:    dir,
+		Author: author,
+		Name:   name,
+		Title:  strings.Title(strings.NewReplacer("_", " ", "-", " ").Replace(name)),
+		Parent: parent,
+	}
+
+	if parent != "" {
+		if !themeRegex.MatchString(parent) {
+			return nil, fmt.Errorf("scaffold: parent theme name must use a-z, 0-9, _ and -, got %s", parent)
+		}
+		if !helper.FileExists(filepath.Join(themesPath, parent, "theme.yml")) {
+			return nil, fmt.Errorf("scaffold: parent theme %s not found", parent)
+		}
+		return write(d, map[string]string{filepath.Join(dir, "theme.yml"): "theme.yml.tmpl"})
+	}
+
+	return write(d, map[string]string{
+		filepath.Join(dir, "theme.yml"):               "theme.yml.tmpl",
+		filepath.Join(dir, "layouts", "default.html"): "theme_layout.html.tmpl",
+		filepath.Join(dir, "pages", "home.html"):      "theme_home.html.tmpl",
+		filepath.Join(dir, "pages", "404.html"):       "theme_404.html.tmpl",
+		filepath.Join(dir, "pages", "error.html"):     "theme_error.html.tmpl",
+	})
+}
+
+func newData(opts Options, author, name string) data {
+	dir := filepath.ToSlash(filepath.Join(opts.PluginsPath, author, name))
+	return data{
+		Module:        opts.Module,
+		Package:       opts.Module + "/" + strings.TrimPrefix(dir, "./"),
+		Dir:           dir,
+		Author:        author,
+		Name:          name,
+		Code:          author + "_" + name,
+		Title:         strings.Title(strings.ReplaceAll(name, "_", " ")),
+		SpringVersion: spring.Version,
+	}
+}
+
+func write(d data, targets map[string]string) ([]string, error) {
+	files := make([]string, 0, len(targets))
+	for file := range targets {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	created := make([]string, 0, len(targets))
+	for _, file := range files {
+		tmpl := targets[file]
+		if _, err := os.Stat(file); err == nil {
+			return created, fmt.Errorf("%w: %s", ErrExists, file)
+		}
+
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, tmpl, d); err != nil {
+			return created, fmt.Errorf("scaffold: %s: %w", tmpl, err)
+		}
+		if err := writeFile(file, buf.Bytes(), strings.HasSuffix(file, ".go")); err != nil {
+			return created, err
+		}
+		created = append(created, file)
+	}
+	return created, nil
+}
+
+func writeFile(file string, b []byte, gofmt bool) error {
+	if gofmt {
+		formatted, err := format.Source(b)
+		if err != nil {
+			return fmt.Errorf("scaffold: %s: %w", file, err)
+		}
+		b = formatted
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("scaffold: %w", err)
+	}
+	if err := os.WriteFile(file, b, 0644); err != nil {
+		return fmt.Errorf("scaffold: %w", err)
+	}
+	return nil
+}
+
+// addMakeTarget puts the build target in front of the build target of the app, or at the end.
+func addMakeTarget(makefile string, d data) error {
+	b, err := os.ReadFile(makefile)
+	if err != nil {
+		return fmt.Errorf("scaffold: %w", err)
+	}
+
+	target := "build_plugin_" + d.Code + ":"
+	if bytes.Contains(b, []byte(target)) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err = templates.ExecuteTemplate(&buf, "makefile.tmpl", d); err != nil {
+		return fmt.Errorf("scaffold: makefile.tmpl: %w", err)
+	}
+
+	anchor := []byte(".PHONY: build\n")
+	if i := bytes.Index(b, anchor); i != -1 {
+		b = append(b[:i:i], append(buf.Bytes(), b[i:]...)...)
+	} else {
+		b = append(append(b, '\n'), buf.Bytes()...)
+	}
+
+	return writeFile(makefile, b, false)
+}
+
+func snake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(rune(s[i-1])) || (i+1 < len(s) && unicode.IsLower(rune(s[i+1])))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
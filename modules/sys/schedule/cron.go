@@ -0,0 +1,230 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Schedule returns the next activation time after t.
+	Schedule interface {
+		Next(t time.Time) time.Time
+	}
+
+	cronSchedule struct {
+		minute, hour, dom, month, dow uint64
+		anyDom, anyDow                bool
+	}
+
+	everySchedule struct {
+		every time.Duration
+	}
+
+	bounds struct {
+		min, max uint
+		names    map[string]uint
+	}
+)
+
+var (
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	doms    = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as Sunday and folded into 0 after the field is parsed
+	dows = bounds{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	descriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Parse parses a standard five field cron expression (minute, hour, day of month, month, day of week),
+// one of the @yearly, @monthly, @weekly, @daily and @hourly descriptors or "@every <duration>".
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("schedule: %s: %w", spec, err)
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("schedule: %s: interval must be at least one second", spec)
+		}
+		return &everySchedule{every: d}, nil
+	}
+
+	if expr, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule: %s: expected 5 fields, found %d", spec, len(fields))
+	}
+
+	s := new(cronSchedule)
+	var err error
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, fmt.Errorf("schedule: %s: minute: %w", spec, err)
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, fmt.Errorf("schedule: %s: hour: %w", spec, err)
+	}
+	if s.dom, err = parseField(fields[2], doms); err != nil {
+		return nil, fmt.Errorf("schedule: %s: day of month: %w", spec, err)
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, fmt.Errorf("schedule: %s: month: %w", spec, err)
+	}
+	if s.dow, err = parseField(fields[4], dows); err != nil {
+		return nil, fmt.Errorf("schedule: %s: day of week: %w", spec, err)
+	}
+	if has(s.dow, 7) {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.anyDom = fields[2] == "*" || fields[2] == "?"
+	s.anyDow = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+func MustParse(spec string) Schedule {
+	s, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Next is aligned to multiples of the interval, so every replica computes the same activation times.
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.every).Add(s.every)
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, uint(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.hour, uint(t.Hour())) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.minute, uint(t.Minute())) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay follows cron semantics: when both day fields are restricted, either of them may match.
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	domMatch := has(s.dom, uint(t.Day()))
+	dowMatch := has(s.dow, uint(t.Weekday()))
+	if s.anyDom || s.anyDow {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		r, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= r
+	}
+	return bits, nil
+}
+
+func parseRange(expr string, b bounds) (uint64, error) {
+	step := uint(1)
+	if index := strings.IndexRune(expr, '/'); index != -1 {
+		s, err := strconv.ParseUint(expr[index+1:], 10, 8)
+		if err != nil || s == 0 {
+			return 0, fmt.Errorf("invalid step %q", expr[index+1:])
+		}
+		step = uint(s)
+		expr = expr[:index]
+	}
+
+	var start, end uint
+	switch {
+	case expr == "*" || expr == "?":
+		start, end = b.min, b.max
+	case strings.ContainsRune(expr, '-'):
+		index := strings.IndexRune(expr, '-')
+		var err error
+		if start, err = parseValue(expr[:index], b); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(expr[index+1:], b); err != nil {
+			return 0, err
+		}
+	default:
+		value, err := parseValue(expr, b)
+		if err != nil {
+			return 0, err
+		}
+		start, end = value, value
+		if step > 1 {
+			end = b.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range %d-%d", start, end)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (uint, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if uint(n) < b.min || uint(n) > b.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, b.min, b.max)
+	}
+	return uint(n), nil
+}
+
+func has(bits uint64, value uint) bool {
+	return bits&(1<<value) != 0
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayOfWeek(t *testing.T) {
+	tests := []struct {
+		spec string
+		days []time.Weekday
+	}{
+		{"0 9 * * 0", []time.Weekday{time.Sunday}},
+		{"0 9 * * 7", []time.Weekday{time.Sunday}},
+		{"0 9 * * 1-7", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}},
+		{"0 9 * * 5-7", []time.Weekday{time.Friday, time.Saturday, time.Sunday}},
+		{"0 9 * * 0,7", []time.Weekday{time.Sunday}},
+		{"0 9 * * 17", nil},
+		{"0 9 * * mon-fri", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"0 9 * * */2", []time.Weekday{time.Sunday, time.Tuesday, time.Thursday, time.Saturday}},
+		{"0 9 * * *", []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if tt.days == nil {
+			if err == nil {
+				t.Errorf("Parse(%q) expected an error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+
+		var want uint64
+		for _, d := range tt.days {
+			want |= 1 << uint(d)
+		}
+		if got := s.(*cronSchedule).dow; got != want {
+			t.Errorf("Parse(%q) days = %07b, want %07b", tt.spec, got, want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * * 3-1",
+		"*/0 * * * *",
+		"@every 10ms",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2021, time.October, 13, 10, 30, 15, 0, time.UTC) // Wednesday
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2021, time.October, 13, 10, 31, 0, 0, time.UTC)},
+		{"0 9 * * 1-7", time.Date(2021, time.October, 14, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2021, time.October, 17, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 5-7", time.Date(2021, time.October, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2021, time.November, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 0", time.Date(2021, time.October, 17, 0, 0, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2021, time.October, 13, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := MustParse(tt.spec).Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/labstack/echo/v4"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const keyPrefix = "schedule:"
+
+type (
+	Scheduler struct {
+		mu    sync.RWMutex
+		cfg   config.Schedule
+		rdb   redis.UniversalClient
+		log   echo.Logger
+		host  string
+		tasks map[string]*entry
+	}
+
+	entry struct {
+		name     string
+		task     Task
+		schedule Schedule
+		running  int32
+	}
+)
+
+func New(cfg config.Schedule, rdb redis.UniversalClient, log echo.Logger) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		cfg:   cfg,
+		rdb:   rdb,
+		log:   log,
+		host:  fmt.Sprintf("%s:%d", host, os.Getpid()),
+		tasks: make(map[string]*entry),
+	}
+}
+
+func (s *Scheduler) Add(name string, task Task) error {
+	if task.Run == nil {
+		return fmt.Errorf("schedule: task %s has no run function", name)
+	}
+
+	sch, err := Parse(task.Spec)
+	if err != nil {
+		return fmt.Errorf("schedule: task %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[name] = &entry{name: name, task: task, schedule: sch}
+	return nil
+}
+
+func (s *Scheduler) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tasks)
+}
+
+// Run starts the scheduler and blocks until one of the shutdown signals is caught.
+func (s *Scheduler) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.graceful(cancel, syscall.SIGABRT, syscall.SIGQUIT, syscall.SIGHUP, os.Interrupt, syscall.SIGTERM)
+
+	return s.Work(ctx)
+}
+
+// Work triggers the due tasks until ctx is done, then waits for the running ones.
+// Each activation is guarded by a Redis lock, so with several replicas a task runs only once per activation.
+func (s *Scheduler) Work(ctx context.Context) error {
+	s.log.Infof("schedule: started with %d task(s)", s.Len())
+
+	taskCtx, cancelTasks := context.WithCancel(context.Background())
+	defer cancelTasks()
+
+	wg := new(sync.WaitGroup)
+	next := make(map[string]time.Time)
+
+	for {
+		now := time.Now()
+		wake := now.Add(time.Minute)
+
+		for _, e := range s.entries() {
+			at, ok := next[e.name]
+			if !ok {
+				at = e.schedule.Next(now)
+				next[e.name] = at
+			}
+			if !at.After(now) {
+				wg.Add(1)
+				go func(e *entry, at time.Time) {
+					defer wg.Done()
+					s.trigger(taskCtx, e, at)
+				}(e, at)
+				at = e.schedule.Next(now)
+				next[e.name] = at
+			}
+			if !at.IsZero() && at.Before(wake) {
+				wake = at
+			}
+		}
+
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.wait(wg, cancelTasks)
+			s.log.Info("schedule: stopped")
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Status returns the registered tasks together with the outcome of their last run on any replica.
+func (s *Scheduler) Status(ctx context.Context) ([]TaskStatus, error) {
+	now := time.Now()
+	entries := s.entries()
+	items := make([]TaskStatus, 0, len(entries))
+
+	for _, e := range entries {
+		item := TaskStatus{
+			Name:        e.name,
+			Spec:        e.task.Spec,
+			Description: e.task.Description,
+			NextRun:     e.schedule.Next(now),
+		}
+
+		values, err := s.rdb.HGetAll(ctx, statusKey(e.name)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("schedule: status %s: %w", e.name, err)
+		}
+		item.LastRun = parseTime(values["last_run"])
+		item.FinishedAt = parseTime(values["finished_at"])
+		item.Status = values["status"]
+		item.Error = values["error"]
+		item.Duration = values["duration"]
+		item.Host = values["host"]
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *Scheduler) trigger(ctx context.Context, e *entry, at time.Time) {
+	// a slow run is not started again on this replica until it is done
+	if !atomic.CompareAndSwapInt32(&e.running, 0, 1) {
+		s.log.Warnf("schedule: %s is still running, skipping %s", e.name, at.Format(time.RFC3339))
+		return
+	}
+	defer atomic.StoreInt32(&e.running, 0)
+
+	lockKey := keyPrefix + "lock:" + e.name + ":" + strconv.FormatInt(at.Unix(), 10)
+	ok, err := s.rdb.SetNX(ctx, lockKey, s.host, s.cfg.LockTTL).Result()
+	if err != nil {
+		s.log.Errorf("schedule: %s: lock: %v", e.name, err)
+		return
+	}
+	if !ok {
+		s.log.Debugf("schedule: %s at %s is handled by another replica", e.name, at.Format(time.RFC3339))
+		return
+	}
+
+	s.log.Infof("schedule: running %s", e.name)
+
+	started := time.Now()
+	s.record(ctx, e.name, map[string]interface{}{
+		"last_run":    started.Format(time.RFC3339Nano),
+		"finished_at": "",
+		"status":      StatusRunning,
+		"error":       "",
+		"duration":    "",
+		"host":        s.host,
+	})
+
+	err = s.run(ctx, e)
+
+	values := map[string]interface{}{
+		"finished_at": time.Now().Format(time.RFC3339Nano),
+		"status":      StatusSuccess,
+		"duration":    time.Since(started).String(),
+	}
+	if err != nil {
+		s.log.Errorf("schedule: %s failed: %v", e.name, err)
+		values["status"] = StatusFailed
+		values["error"] = err.Error()
+	}
+	s.record(ctx, e.name, values)
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry) (err error) {
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return e.task.Run(ctx)
+}
+
+func (s *Scheduler) record(ctx context.Context, name string, values map[string]interface{}) {
+	if err := s.rdb.HSet(ctx, statusKey(name), values).Err(); err != nil {
+		s.log.Errorf("schedule: %s: status: %v", name, err)
+	}
+}
+
+func (s *Scheduler) wait(wg *sync.WaitGroup, cancelTasks context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if s.cfg.ShutdownTimeout <= 0 {
+		<-done
+		return
+	}
+
+	select {
+	case <-done:
+	case <-time.After(s.cfg.ShutdownTimeout):
+		s.log.Warnf("schedule: running tasks were cancelled after %s", s.cfg.ShutdownTimeout)
+		cancelTasks()
+		<-done
+	}
+}
+
+func (s *Scheduler) entries() []*entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*entry, 0, len(s.tasks))
+	for _, e := range s.tasks {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+func (s *Scheduler) graceful(cancel context.CancelFunc, signals ...os.Signal) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	sig := <-quit
+
+	s.log.Infof("Caught signal %s. Shutting down...", sig)
+	cancel()
+}
+
+func statusKey(name string) string {
+	return keyPrefix + "status:" + name
+}
+
+func parseTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	StatusRunning = "running"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+type (
+	TaskFunc func(ctx context.Context) error
+
+	Task struct {
+		Spec        string
+		Description string
+		Run         TaskFunc
+	}
+
+	TaskMap map[string]Task
+
+	PluginRegisterSchedule interface {
+		RegisterSchedule() TaskMap
+	}
+
+	TaskStatus struct {
+		Name        string     `json:"name"`
+		Spec        string     `json:"spec"`
+		Description string     `json:"description,omitempty"`
+		NextRun     time.Time  `json:"next_run"`
+		LastRun     *time.Time `json:"last_run,omitempty"`
+		FinishedAt  *time.Time `json:"finished_at,omitempty"`
+		Status      string     `json:"status,omitempty"`
+		Error       string     `json:"error,omitempty"`
+		Duration    string     `json:"duration,omitempty"`
+		Host        string     `json:"host,omitempty"`
+	}
+)
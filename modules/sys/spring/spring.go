@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/iagapie/go-spring/modules/sys/asset"
 	"github.com/iagapie/go-spring/modules/sys/config"
+	"github.com/iagapie/go-spring/modules/sys/mail"
+	"github.com/iagapie/go-spring/modules/sys/media"
 	middleware2 "github.com/iagapie/go-spring/modules/sys/middleware"
+	"github.com/iagapie/go-spring/modules/sys/queue"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -29,6 +34,10 @@ type (
 		Frontend *Frontend
 		Backend  *Backend
 		Cfg      config.Cfg
+		Assets   *asset.Combiner
+		Mail     *mail.Manager
+		Media    *media.Manager
+		Queue    *queue.Queue
 	}
 
 	Frontend struct {
@@ -37,6 +46,9 @@ type (
 
 	Backend struct {
 		*echo.Group
+		// JWTMiddleware and UserMiddleware are set by the web command for the plugin routes requiring a signed in user.
+		JWTMiddleware  echo.MiddlewareFunc
+		UserMiddleware echo.MiddlewareFunc
 	}
 
 	Context interface {
@@ -66,6 +78,7 @@ func New(cfg config.Cfg, l echo.Logger) *Spring {
 	s.Logger = l
 	s.StdLogger = log.New(s.Logger.Output(), s.Logger.Prefix()+": ", 0)
 	s.Validator = &valid{validator.New()}
+	s.IPExtractor = ipExtractor(cfg.App.TrustedProxies, l)
 
 	s.Use(
 		func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -95,6 +108,34 @@ func New(cfg config.Cfg, l echo.Logger) *Spring {
 	return s
 }
 
+// ipExtractor trusts X-Forwarded-For only from the configured proxies, a client could set it otherwise.
+func ipExtractor(proxies []string, l echo.Logger) echo.IPExtractor {
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range proxies {
+		if proxy = strings.TrimSpace(proxy); len(proxy) == 0 {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			l.Warnf("trusted proxy %s: %v", proxy, err)
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	if len(options) == 3 {
+		return echo.ExtractIPDirect()
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 func (s *Spring) Run() error {
 	s.Logger.Printf("Spring v%s, Echo v%s", Version, echo.Version)
 
@@ -13,6 +13,7 @@ import (
 type jwtToken struct {
 	privateKey []byte
 	publicKey  []byte
+	audience   string
 }
 
 type JWTOption interface {
@@ -32,6 +33,13 @@ func WithJWTKeys(jwtKeys config.JWTKeys) JWTOption {
 	})
 }
 
+// WithAudience sets the aud claim of the created tokens, only tokens with the same audience are valid.
+func WithAudience(audience string) JWTOption {
+	return jwtOption(func(jt *jwtToken) {
+		jt.audience = audience
+	})
+}
+
 func WithPrivateKey(privateKey []byte) JWTOption {
 	return jwtOption(func(jt *jwtToken) {
 		jt.privateKey = privateKey
@@ -85,6 +93,9 @@ func (jt *jwtToken) Create(ttl time.Duration, content interface{}) (string, erro
 	claims["exp"] = now.Add(ttl).Unix() // The expiration time after which the token must be disregarded.
 	claims["iat"] = now.Unix()          // The time at which the token was issued.
 	claims["nbf"] = now.Unix()          // The time before which the token must be disregarded.
+	if jt.audience != "" {
+		claims["aud"] = jt.audience // The recipient the token is intended for.
+	}
 
 	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
 	if err != nil {
@@ -95,13 +106,21 @@ func (jt *jwtToken) Create(ttl time.Duration, content interface{}) (string, erro
 }
 
 func (jt *jwtToken) Validate(token string) (interface{}, error) {
+	claims, err := jt.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	return claims.Data, nil
+}
+
+func (jt *jwtToken) Parse(token string) (Claims, error) {
 	if jt.publicKey == nil {
-		return nil, errors.New("validate: public key is nil")
+		return Claims{}, errors.New("validate: public key is nil")
 	}
 
 	key, err := jwt.ParseRSAPublicKeyFromPEM(jt.publicKey)
 	if err != nil {
-		return nil, fmt.Errorf("validate: parse key: %w", err)
+		return Claims{}, fmt.Errorf("validate: parse key: %w", err)
 	}
 
 	tok, err := jwt.Parse(token, func(jwtToken *jwt.Token) (interface{}, error) {
@@ -112,13 +131,22 @@ func (jt *jwtToken) Validate(token string) (interface{}, error) {
 		return key, nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("validate: %w", err)
+		return Claims{}, fmt.Errorf("validate: %w", err)
 	}
 
 	claims, ok := tok.Claims.(jwt.MapClaims)
 	if !ok || !tok.Valid {
-		return nil, errors.New("validate: invalid")
+		return Claims{}, errors.New("validate: invalid")
+	}
+
+	if aud, _ := claims["aud"].(string); aud != jt.audience {
+		return Claims{}, fmt.Errorf("validate: audience %q is not %q", aud, jt.audience)
+	}
+
+	var issuedAt time.Time
+	if iat, ok := claims["iat"].(float64); ok {
+		issuedAt = time.Unix(int64(iat), 0)
 	}
 
-	return claims["dat"], nil
+	return Claims{Data: claims["dat"], IssuedAt: issuedAt}, nil
 }
@@ -2,7 +2,22 @@ package token
 
 import "time"
 
-type Token interface {
-	Create(ttl time.Duration, content interface{}) (string, error)
-	Validate(token string) (interface{}, error)
-}
+const (
+	// AudienceAccess is the audience of the backend access tokens.
+	AudienceAccess = "access"
+	// AudienceAccount is the audience of the password reset and email verification tokens.
+	AudienceAccount = "account"
+)
+
+type (
+	Token interface {
+		Create(ttl time.Duration, content interface{}) (string, error)
+		Validate(token string) (interface{}, error)
+		Parse(token string) (Claims, error)
+	}
+
+	Claims struct {
+		Data     interface{}
+		IssuedAt time.Time
+	}
+)
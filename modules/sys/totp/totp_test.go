@@ -0,0 +1,76 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// secret of the SHA1 test vectors of RFC 6238, appendix B
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeRFC6238(t *testing.T) {
+	// the RFC lists 8 digits, the codes here are the last 6
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, v := range vectors {
+		got, err := Code(rfcSecret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.code {
+			t.Errorf("Code at %d = %s, want %s", v.unix, got, v.code)
+		}
+		if !Validate(rfcSecret, v.code, time.Unix(v.unix, 0)) {
+			t.Errorf("Validate at %d rejected %s", v.unix, v.code)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	for _, d := range []time.Duration{-Period * time.Second, Period * time.Second} {
+		c, _ := Code(rfcSecret, now.Add(d))
+		if !Validate(rfcSecret, c, now) {
+			t.Errorf("code of %s was rejected", d)
+		}
+	}
+	c, _ := Code(rfcSecret, now.Add(-2*Period*time.Second))
+	if Validate(rfcSecret, c, now) {
+		t.Error("code of two steps ago was accepted")
+	}
+	if Validate(rfcSecret, "12345", now) || Validate(rfcSecret, "081804", now.Add(time.Hour)) {
+		t.Error("invalid code was accepted")
+	}
+}
+
+func TestValidateStepReplay(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	c, _ := Code(rfcSecret, now)
+
+	step, ok := ValidateStep(rfcSecret, c, now, 0)
+	if !ok || step != now.Unix()/Period {
+		t.Fatalf("ValidateStep = %d, %v", step, ok)
+	}
+	if _, ok = ValidateStep(rfcSecret, c, now, step); ok {
+		t.Error("code was accepted twice")
+	}
+	if _, ok = ValidateStep(rfcSecret, c, now.Add(Period*time.Second), step); ok {
+		t.Error("code was accepted again in the next step")
+	}
+
+	next, _ := Code(rfcSecret, now.Add(Period*time.Second))
+	if s, ok := ValidateStep(rfcSecret, next, now, step); !ok || s != step+1 {
+		t.Errorf("code of the next step = %d, %v", s, ok)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	texttemplate "text/template"
 )
 
 type (
@@ -46,6 +47,7 @@ type (
 		mu         sync.Mutex
 		file       string
 		content    string
+		source     *string
 		props      Props
 		comps      Comps
 		delimLeft  string
@@ -53,7 +55,12 @@ type (
 		start      string
 		end        string
 		funcs      template.FuncMap
-		t          *template.Template
+		text       bool
+		t          executor
+	}
+
+	executor interface {
+		Execute(w io.Writer, data interface{}) error
 	}
 )
 
@@ -77,6 +84,20 @@ func WithCfgSep(start, end string) Option {
 	})
 }
 
+// WithContent makes the view use content instead of reading it from its file.
+func WithContent(content string) Option {
+	return option(func(v *view) {
+		v.source = &content
+	})
+}
+
+// WithText parses the view with text/template, so the output is not HTML escaped (e.g. plain text emails).
+func WithText() Option {
+	return option(func(v *view) {
+		v.text = true
+	})
+}
+
 func WithDelims(left, right string) Option {
 	return option(func(v *view) {
 		v.delimLeft = left
@@ -112,7 +133,7 @@ func (v *view) Name() string {
 }
 
 func (v *view) Exists() bool {
-	return helper.FileExists(v.File())
+	return v.source != nil || helper.FileExists(v.File())
 }
 
 func (v *view) Props() Props {
@@ -175,6 +196,10 @@ func (v *view) Render(vars interface{}) (string, error) {
 }
 
 func (v *view) read() error {
+	if v.source != nil {
+		v.content = *v.source
+		return nil
+	}
 	if !v.Exists() {
 		return fmt.Errorf("view %s not found", v.File())
 	}
@@ -225,9 +250,21 @@ func (v *view) parseCfg(cfg string) error {
 	return nil
 }
 
-func (v *view) parse() (err error) {
-	v.t, err = template.New(v.File()).Funcs(v.funcs).Parse(v.Content())
-	return
+func (v *view) parse() error {
+	if v.text {
+		t, err := texttemplate.New(v.File()).Funcs(texttemplate.FuncMap(v.funcs)).Parse(v.Content())
+		if err != nil {
+			return err
+		}
+		v.t = t
+		return nil
+	}
+	t, err := template.New(v.File()).Funcs(v.funcs).Parse(v.Content())
+	if err != nil {
+		return err
+	}
+	v.t = t
+	return nil
 }
 
 func (fn option) apply(v *view) {
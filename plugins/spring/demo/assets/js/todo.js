@@ -0,0 +1,5 @@
+(function () {
+  document.querySelectorAll(".todo").forEach(function (el) {
+    el.classList.add("todo--ready");
+  });
+})();
@@ -49,7 +49,7 @@ func (*todo) CfgProps() component.CfgProps {
 func (t *todo) Init(s *spring.Spring) {
 	log.Info("component todo Init()")
 	log.Info(s.Cfg.App.Name)
-	t.v.AddJS("js/test.js", view.Param{
+	t.v.AddJS("~/js/todo.js", view.Param{
 		Name: "defer",
 		Value: true,
 	})
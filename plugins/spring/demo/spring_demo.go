@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/iagapie/go-spring/modules/cms/component"
 	"github.com/iagapie/go-spring/modules/sys/plugin"
+	"github.com/iagapie/go-spring/modules/sys/schedule"
 	"github.com/iagapie/go-spring/modules/sys/spring"
 	"github.com/iagapie/go-spring/modules/sys/view"
 	"github.com/iagapie/go-spring/plugins/spring/demo/components"
@@ -44,6 +46,19 @@ func (p *plug) RegisterComponents() component.FactoryMap {
 	}
 }
 
+func (p *plug) RegisterSchedule() schedule.TaskMap {
+	return schedule.TaskMap{
+		"spring_demo.heartbeat": {
+			Spec:        "@hourly",
+			Description: "Logs a heartbeat message",
+			Run: func(ctx context.Context) error {
+				p.s.Logger.Infof("%s heartbeat", p.details.Name)
+				return nil
+			},
+		},
+	}
+}
+
 func init() {
 	Plugin = &plug{
 		details: plugin.Details{
@@ -52,6 +67,7 @@ func init() {
 			Version:     "1.0.0",
 			Description: "This is a spring demo plugin",
 			Author:      "Igor Agapie",
+			Spring:      "1.0.0",
 		},
 	}
 }
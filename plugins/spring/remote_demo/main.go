@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"github.com/iagapie/go-spring/modules/cms/component"
+	"github.com/iagapie/go-spring/modules/sys/plugin/remote"
+	"log"
+	"net/http"
+	"os"
+)
+
+func main() {
+	// stdout is used by the protocol
+	log.SetOutput(os.Stderr)
+
+	log.Fatal(remote.Serve(&remote.Server{
+		Routes: []remote.Route{
+			{
+				Area:   remote.AreaFrontend,
+				Method: http.MethodGet,
+				Path:   "/remote/:name",
+				Name:   "remote-welcome",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+					fmt.Fprintf(w, "Hello %s from pid %d", remote.Param(r, "name"), os.Getpid())
+				},
+			},
+		},
+		Components: []remote.Component{
+			{
+				Details: component.Details{
+					Code:     "remote_hello",
+					Name:     "Remote Hello Component",
+					ViewFile: "default",
+				},
+				Handlers: map[string]func(call *remote.Call) (interface{}, error){
+					"OnHello": func(call *remote.Call) (interface{}, error) {
+						return map[string]interface{}{
+							"message": fmt.Sprintf("Hello %s", call.Request.Form.Get("name")),
+						}, nil
+					},
+				},
+			},
+		},
+	}))
+}